@@ -160,3 +160,287 @@ CREATE TABLE IF NOT EXISTS cars (
     rear_left_mudguard part_condition,
     rear_bumper part_condition
 );
+
+CREATE TABLE IF NOT EXISTS user_status_changes (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    changed_by VARCHAR(255) NOT NULL REFERENCES users(id),
+    old_status VARCHAR(50) NOT NULL,
+    new_status VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- accounts created before email verification was introduced are treated as verified
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS two_factor_secret VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, code_hash)
+);
+
+-- accounts created through social login have no phone number until the user adds one
+ALTER TABLE users ALTER COLUMN phone_number DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_identities (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, subject)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS login_events (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NULL REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    ip VARCHAR(64) NOT NULL,
+    user_agent TEXT NOT NULL,
+    method VARCHAR(50) NOT NULL,
+    outcome VARCHAR(20) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS login_events_user_id_idx ON login_events (user_id, created_at);
+CREATE INDEX IF NOT EXISTS login_events_created_at_idx ON login_events (created_at);
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'Active';
+
+-- existing sellers already exposed their number on every listing
+ALTER TABLE users ADD COLUMN IF NOT EXISTS show_phone_number BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE TABLE IF NOT EXISTS contact_reveals (
+    id VARCHAR(255) PRIMARY KEY,
+    car_id VARCHAR(255) NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    seller_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    viewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ip VARCHAR(64) NOT NULL,
+    masked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS contact_reveals_car_id_idx ON contact_reveals (car_id);
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS view_count INT NOT NULL DEFAULT 0;
+
+-- listings created before expiry was introduced get a full lifetime from now
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP NULL;
+UPDATE cars SET expires_at = NOW() + INTERVAL '60 days' WHERE expires_at IS NULL;
+ALTER TABLE cars ALTER COLUMN expires_at SET NOT NULL;
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    tax_number VARCHAR(20) NOT NULL UNIQUE,
+    tax_office VARCHAR(255) NOT NULL,
+    address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    country_code VARCHAR(5) NOT NULL,
+    phone_number VARCHAR(20) NOT NULL,
+    logo_url VARCHAR(255) NOT NULL DEFAULT '',
+    verified BOOLEAN NOT NULL DEFAULT FALSE,
+    verified_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id VARCHAR(255) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS organization_members_user_id_idx ON organization_members (user_id);
+
+CREATE TABLE IF NOT EXISTS organization_invitations (
+    id VARCHAR(255) PRIMARY KEY,
+    organization_id VARCHAR(255) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    invited_by VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    UNIQUE (organization_id, email)
+);
+
+CREATE INDEX IF NOT EXISTS organization_invitations_email_idx ON organization_invitations (email);
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS organization_id VARCHAR(255) NULL REFERENCES organizations(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS cars_organization_id_idx ON cars (organization_id);
+
+-- dealer badges now come from verified organizations, not self-declaration
+UPDATE cars SET seller_type = 'Individual' WHERE organization_id IS NULL AND seller_type = 'Dealer';
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS stock_number VARCHAR(100) NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS cars_organization_stock_number_idx ON cars (organization_id, stock_number);
+
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id VARCHAR(255) PRIMARY KEY,
+    organization_id VARCHAR(255) NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL,
+    full_sync BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    total_rows INT NOT NULL DEFAULT 0,
+    created_count INT NOT NULL DEFAULT 0,
+    updated_count INT NOT NULL DEFAULT 0,
+    removed_count INT NOT NULL DEFAULT 0,
+    failed_count INT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS import_jobs_organization_id_idx ON import_jobs (organization_id, created_at);
+-- at most one queued or processing import per organization; older duplicates
+-- left by the racy check are failed first so the index can be built
+UPDATE import_jobs j SET status = 'Failed', error = 'Superseded by a newer import.', finished_at = NOW()
+WHERE j.status IN ('Queued', 'Processing') AND EXISTS (
+    SELECT 1 FROM import_jobs n
+    WHERE n.organization_id = j.organization_id AND n.status IN ('Queued', 'Processing') AND n.created_at > j.created_at
+);
+CREATE UNIQUE INDEX IF NOT EXISTS import_jobs_unfinished_idx ON import_jobs (organization_id) WHERE status IN ('Queued', 'Processing');
+
+CREATE TABLE IF NOT EXISTS import_job_rows (
+    job_id VARCHAR(255) NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    row_index INT NOT NULL,
+    stock_number VARCHAR(100) NOT NULL,
+    result VARCHAR(20) NOT NULL,
+    car_id VARCHAR(255) NULL,
+    errors TEXT[] NULL,
+    PRIMARY KEY (job_id, row_index)
+);
+
+CREATE TABLE IF NOT EXISTS favorites (
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    car_id VARCHAR(255) NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, car_id)
+);
+
+CREATE INDEX IF NOT EXISTS favorites_car_id_idx ON favorites (car_id);
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    brand_id INT NOT NULL DEFAULT 0,
+    series_id INT NOT NULL DEFAULT 0,
+    model_id INT NOT NULL DEFAULT 0,
+    frequency VARCHAR(20) NOT NULL,
+    unsubscribe_token VARCHAR(64) NOT NULL UNIQUE,
+    last_run_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS saved_searches_user_id_idx ON saved_searches (user_id);
+CREATE INDEX IF NOT EXISTS cars_listing_date_idx ON cars (listing_date);
+
+CREATE TABLE IF NOT EXISTS car_price_history (
+    id SERIAL PRIMARY KEY,
+    car_id VARCHAR(255) NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    old_price NUMERIC(10, 2) NOT NULL,
+    old_currency currency NOT NULL,
+    new_price NUMERIC(10, 2) NOT NULL,
+    new_currency currency NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS car_price_history_car_id_idx ON car_price_history (car_id, changed_at);
+
+CREATE TABLE IF NOT EXISTS conversations (
+    id VARCHAR(255) PRIMARY KEY,
+    car_id VARCHAR(255) NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    seller_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    buyer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_message_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (car_id, buyer_id)
+);
+
+CREATE INDEX IF NOT EXISTS conversations_seller_id_idx ON conversations (seller_id, last_message_at);
+CREATE INDEX IF NOT EXISTS conversations_buyer_id_idx ON conversations (buyer_id, last_message_at);
+
+CREATE TABLE IF NOT EXISTS messages (
+    id VARCHAR(255) PRIMARY KEY,
+    conversation_id VARCHAR(255) NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    sender_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL DEFAULT '',
+    attachments TEXT[],
+    read_at TIMESTAMP,
+    notified_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS messages_conversation_id_idx ON messages (conversation_id, created_at);
+CREATE INDEX IF NOT EXISTS messages_unnotified_idx ON messages (created_at) WHERE read_at IS NULL AND notified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker_id, blocked_id)
+);
+
+CREATE TABLE IF NOT EXISTS offers (
+    id VARCHAR(255) PRIMARY KEY,
+    car_id VARCHAR(255) NOT NULL REFERENCES cars(id) ON DELETE CASCADE,
+    buyer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    seller_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount NUMERIC(10, 2) NOT NULL,
+    currency currency NOT NULL,
+    trade_in_car_id VARCHAR(255) REFERENCES cars(id) ON DELETE SET NULL,
+    message TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS offers_buyer_id_idx ON offers (buyer_id, updated_at);
+CREATE INDEX IF NOT EXISTS offers_seller_id_idx ON offers (seller_id, updated_at);
+CREATE INDEX IF NOT EXISTS offers_open_idx ON offers (expires_at) WHERE status IN ('pending', 'countered');
+CREATE UNIQUE INDEX IF NOT EXISTS offers_open_car_buyer_idx ON offers (car_id, buyer_id) WHERE status IN ('pending', 'countered');
+
+ALTER TABLE cars ADD COLUMN IF NOT EXISTS expiry_notified_at TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id VARCHAR(255) PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    link TEXT NOT NULL DEFAULT '',
+    data JSONB,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_idx ON notifications (user_id, created_at);
+CREATE INDEX IF NOT EXISTS notifications_unread_idx ON notifications (user_id) WHERE read_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    in_app BOOLEAN NOT NULL DEFAULT TRUE,
+    email BOOLEAN NOT NULL DEFAULT TRUE,
+    PRIMARY KEY (user_id, type)
+);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
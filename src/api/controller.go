@@ -1,12 +1,26 @@
 package main
 
 import (
+	"archive/zip"
+	"bytes"
 	"carwise"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"image/png"
+	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
+	"path"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,6 +28,31 @@ var (
 	interactor *carwise.Interactor
 )
 
+const (
+	maxFeedSize            = 10 << 20
+	maxAttachmentSize      = 5 << 20
+	eventHeartbeatInterval = 30 * time.Second
+	maxPageLimit           = 100
+)
+
+// parsePagination reads the page and limit query parameters and answers with
+// a 400 when either is malformed or out of range.
+func parsePagination(ctx *gin.Context, defaultLimit int) (int, int, bool) {
+	page, err := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		ctx.JSON(400, gin.H{"error": "Invalid page"})
+		return 0, 0, false
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		ctx.JSON(400, gin.H{"error": "Invalid limit"})
+		return 0, 0, false
+	}
+
+	return page, limit, true
+}
+
 func registerUser(ctx *gin.Context) {
 	var request carwise.UserCreateRequest
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -61,7 +100,7 @@ func loginUser(ctx *gin.Context) {
 		return
 	}
 
-	user, errors := interactor.LoginUser(request)
+	user, errors := interactor.LoginUser(request, ctx.ClientIP(), ctx.Request.UserAgent())
 	if errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": errors,
@@ -69,6 +108,19 @@ func loginUser(ctx *gin.Context) {
 		return
 	}
 
+	if user.TwoFactorEnabled {
+		challenge, errors := interactor.CreateTwoFactorChallenge(user.ID)
+		if errors != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": errors,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge_token": challenge})
+		return
+	}
+
 	token, err := JWTAuthorization(user)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": []string{"Could not generate token"}})
@@ -79,102 +131,114 @@ func loginUser(ctx *gin.Context) {
 
 }
 
-func logoutUser(ctx *gin.Context) {
-	token, exists := ctx.Get("token")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": []string{"No token found in request context"}})
-		return
-	}
-	tokenString := token.(string)
-
-	err := interactor.AddTokenBlackList(tokenString)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error": err,
+func startSocialLogin(ctx *gin.Context) {
+	authorizationURL, errors := interactor.StartSocialLogin(ctx.Param("provider"))
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
 		})
 		return
 	}
-	ctx.Status(http.StatusOK)
+
+	ctx.JSON(http.StatusOK, gin.H{"authorization_url": authorizationURL})
 }
 
-func userProfile(ctx *gin.Context) {
-	userContext, exists := ctx.Get("user")
-	if !exists {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+func socialLoginCallback(ctx *gin.Context) {
+	var request carwise.SocialLoginRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	claim := userContext.(*UserClaims)
 
-	profile, err := interactor.GetProfile(claim.UserId)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error": err,
+	errors := ValidateStruct(request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
 		})
 		return
 	}
-	ctx.JSON(http.StatusOK, profile)
-}
-
-func resetPasswordRequest(ctx *gin.Context) {
-	var request carwise.ResetPasswordRequest
 
-	err := ctx.ShouldBindJSON(&request)
-	if err != nil {
+	user, errors := interactor.SocialLogin(ctx.Param("provider"), request, ctx.ClientIP(), ctx.Request.UserAgent())
+	if errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": []string{err.Error()},
+			"error": errors,
 		})
 		return
 	}
 
-	if err := ValidateStruct(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err,
-		})
+	if user.TwoFactorEnabled {
+		challenge, errors := interactor.CreateTwoFactorChallenge(user.ID)
+		if errors != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{
+				"error": errors,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, gin.H{"two_factor_required": true, "challenge_token": challenge})
 		return
 	}
 
-	if errors := interactor.ResetPasswordRequest(request); errors != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": errors,
-		})
+	token, err := JWTAuthorization(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": []string{"Could not generate token"}})
 		return
 	}
 
-	ctx.Status(http.StatusOK)
+	ctx.JSON(http.StatusOK, gin.H{"access_token": token})
 }
-func resetPassword(ctx *gin.Context) {
-	var request carwise.ChangePasswordRequest
-
-	token := ctx.Query("token")
-	email := ctx.Query("email")
 
-	err := ctx.ShouldBindJSON(&request)
-	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": []string{err.Error()},
-		})
+func loginTwoFactor(ctx *gin.Context) {
+	var request carwise.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := ValidateStruct(&request); err != nil {
+	errors := ValidateStruct(request)
+	if errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err,
+			"error": errors,
 		})
 		return
 	}
 
-	if errors := interactor.ChangePassword(request, token, email); errors != nil {
+	user, errors := interactor.LoginTwoFactor(request, ctx.ClientIP(), ctx.Request.UserAgent())
+	if errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": errors,
 		})
 		return
 	}
 
-	ctx.Status(http.StatusOK)
+	token, err := JWTAuthorization(user)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": []string{"Could not generate token"}})
+		return
+	}
 
+	ctx.JSON(http.StatusOK, gin.H{"access_token": token})
 }
 
-func editUserProfile(ctx *gin.Context) {
+func logoutUser(ctx *gin.Context) {
+	token, exists := ctx.Get("token")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": []string{"No token found in request context"}})
+		return
+	}
+	tokenString := token.(string)
+
+	err := interactor.AddTokenBlackList(tokenString)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err,
+		})
+		return
+	}
+	ctx.Status(http.StatusOK)
+}
+
+func userProfile(ctx *gin.Context) {
 	userContext, exists := ctx.Get("user")
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
@@ -182,126 +246,151 @@ func editUserProfile(ctx *gin.Context) {
 	}
 	claim := userContext.(*UserClaims)
 
-	var request carwise.ProfileEditRequest
-
-	request.FirstName = ctx.Request.FormValue("first_name")
-	request.LastName = ctx.Request.FormValue("last_name")
-	request.CountryCode = ctx.Request.FormValue("country_code")
-	request.PhoneNumber = ctx.Request.FormValue("phone_number")
-
-	if err := ValidateStruct(&request); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
+	profile, err := interactor.GetProfile(claim.UserId)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
 			"error": err,
 		})
 		return
 	}
+	ctx.JSON(http.StatusOK, profile)
+}
 
-	avatar, err := ctx.FormFile("avatar")
-	if avatar != nil && err != nil {
-		if err.Error() != "multipart: no multipart data" {
-			ctx.JSON(http.StatusBadRequest, gin.H{
-				"error": []string{err.Error()},
-			})
-			return
-		}
+func securityEvents(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
 	}
+	claim := userContext.(*UserClaims)
 
-	if avatar != nil && !isValidImageFormat(avatar.Filename) {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": []string{"Invalid file format."},
-		})
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
 		return
 	}
 
-	if errors := interactor.EditProfile(claim.UserId, request, avatar); errors != nil {
+	response, errors := interactor.GetSecurityEvents(claim.UserId, page, limit)
+	if errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": errors,
 		})
 		return
 	}
 
-	ctx.Status(http.StatusOK)
+	ctx.JSON(http.StatusOK, response)
 }
 
-func getBrands(ctx *gin.Context) {
-	brands, err := interactor.GetBrands()
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+func exportProfile(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
 		return
 	}
+	claim := userContext.(*UserClaims)
 
-	ctx.JSON(http.StatusOK, brands)
-}
-
-func listCars(ctx *gin.Context) {
-	limitStr := ctx.DefaultQuery("limit", "20")
-	pageStr := ctx.DefaultQuery("page", "1")
-	brandIDStr := ctx.DefaultQuery("brand_id", "0")
-	seriesIDStr := ctx.DefaultQuery("series_id", "0")
-	modelIDStr := ctx.DefaultQuery("model_id", "0")
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil {
-		log.Println("Error converting limit:", err)
-		ctx.JSON(400, gin.H{"error": "Invalid limit"})
+	export, errors := interactor.ExportAccount(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors,
+		})
 		return
 	}
 
-	page, err := strconv.Atoi(pageStr)
-	if err != nil {
-		log.Println("Error converting page:", err)
-		ctx.JSON(400, gin.H{"error": "Invalid page"})
+	if ctx.Query("format") == "json" {
+		ctx.JSON(http.StatusOK, export)
 		return
 	}
 
-	brandID, err := strconv.Atoi(brandIDStr)
-	if err != nil {
-		log.Println("Error converting brand_id:", err)
-		ctx.JSON(400, gin.H{"error": "Invalid brand_id"})
+	avatar, errors := interactor.GetUserAvatar(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": errors,
+		})
 		return
 	}
+	if avatar != nil {
+		defer avatar.Close()
+	}
 
-	seriesID, err := strconv.Atoi(seriesIDStr)
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	data, err := json.MarshalIndent(export, "", "  ")
 	if err != nil {
-		log.Println("Error converting series_id:", err)
-		ctx.JSON(400, gin.H{"error": "Invalid series_id"})
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": []string{"Could not generate export"}})
 		return
 	}
-
-	modelID, err := strconv.Atoi(modelIDStr)
+	file, err := archive.Create("account.json")
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if err == nil && avatar != nil {
+		file, err = archive.Create("images/avatar.png")
+		if err == nil {
+			_, err = io.Copy(file, avatar)
+		}
+	}
+	for _, conversation := range export.Conversations {
+		for _, message := range conversation.Messages {
+			for _, attachment := range message.Attachments {
+				if err != nil {
+					break
+				}
+				err = writeExportAttachment(archive, claim.UserId, conversation.ID, path.Base(attachment))
+			}
+		}
+	}
+	if err == nil {
+		err = archive.Close()
+	}
 	if err != nil {
-		log.Println("Error converting model_id:", err)
-		ctx.JSON(400, gin.H{"error": "Invalid model_id"})
+		log.Printf("Error writing account export: %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": []string{"Could not generate export"}})
 		return
 	}
 
-	response, errors := interactor.ListCars(page, limit, brandID, seriesID, modelID)
+	filename := "carwise-export-" + export.ExportedAt.Format("2006-01-02") + ".zip"
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Data(http.StatusOK, "application/zip", buffer.Bytes())
+}
+
+func writeExportAttachment(archive *zip.Writer, userId, conversationId, fileName string) error {
+	attachment, errors := interactor.OpenMessageAttachment(userId, conversationId, fileName)
 	if errors != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": errors,
-		})
-		return
+		return fmt.Errorf("failed to open attachment %s: %v", fileName, errors)
 	}
+	if attachment == nil {
+		return nil
+	}
+	defer attachment.Close()
 
-	ctx.JSON(http.StatusOK, response)
-
+	file, err := archive.Create(path.Join("attachments", conversationId, fileName))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, attachment)
+	return err
 }
-func getCarByID(ctx *gin.Context) {
-	id := ctx.Param("id")
 
-	carDetail, err := interactor.GetCarDetail(id)
-	if err != nil {
+func sendAccountDeletionCode(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.SendAccountDeletionCode(claim.UserId); errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
-			"error": err,
+			"error": errors,
 		})
 		return
 	}
-	ctx.JSON(http.StatusOK, carDetail)
 
+	ctx.Status(http.StatusOK)
 }
-func createCar(ctx *gin.Context) {
+
+func deleteProfile(ctx *gin.Context) {
 	userContext, exists := ctx.Get("user")
 	if !exists {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
@@ -309,7 +398,7 @@ func createCar(ctx *gin.Context) {
 	}
 	claim := userContext.(*UserClaims)
 
-	var request carwise.CarCreateRequest
+	var request carwise.DeleteAccountRequest
 	err := ctx.ShouldBindJSON(&request)
 	if err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
@@ -324,7 +413,8 @@ func createCar(ctx *gin.Context) {
 		})
 		return
 	}
-	if errors := interactor.CreateCar(claim.UserId, request); errors != nil {
+
+	if errors := interactor.RequestAccountDeletion(claim.UserId, request); errors != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{
 			"error": errors,
 		})
@@ -332,26 +422,2279 @@ func createCar(ctx *gin.Context) {
 	}
 
 	ctx.Status(http.StatusOK)
-
 }
-func updateCar(c *gin.Context) {
 
-}
-func deleteCar(c *gin.Context) {
-
-}
+func cancelProfileDeletion(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
 
-func predictPrice(c *gin.Context) {
+	if errors := interactor.CancelAccountDeletion(claim.UserId); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
 
+	ctx.Status(http.StatusOK)
 }
-func getPredictionHistory(c *gin.Context) {
 
-}
-func suggestCar(c *gin.Context) {
+func resetPasswordRequest(ctx *gin.Context) {
+	var request carwise.ResetPasswordRequest
+
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ResetPasswordRequest(request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+func resetPassword(ctx *gin.Context) {
+	var request carwise.ChangePasswordRequest
+
+	token := ctx.Query("token")
+	email := ctx.Query("email")
+
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ChangePassword(request, token, email); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+
+}
+
+func verifyEmail(ctx *gin.Context) {
+	var request carwise.VerifyEmailRequest
+
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.VerifyEmail(request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+// confirmationPage asks the user to press a button before a link from an
+// email takes effect. Mail scanners and link previews follow GET links, so
+// the state change only happens on the POST the form sends.
+var confirmationPage = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{.Title}} - Carwise</title>
+</head>
+<body>
+<form method="post" action="{{.Action}}">
+{{range $name, $value := .Fields}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<button type="submit">{{.Title}}</button>
+</form>
+</body>
+</html>
+`))
+
+func renderConfirmationPage(ctx *gin.Context, title string, fields map[string]string) {
+	var page bytes.Buffer
+	err := confirmationPage.Execute(&page, map[string]interface{}{
+		"Title":  title,
+		"Action": ctx.Request.URL.Path,
+		"Fields": fields,
+	})
+	if err != nil {
+		log.Printf("Error rendering confirmation page: %v\n", err)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("Referrer-Policy", "no-referrer")
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+func verifyEmailPage(ctx *gin.Context) {
+	var request carwise.VerifyEmailRequest
+
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	renderConfirmationPage(ctx, "Verify email", map[string]string{
+		"email": request.Email,
+		"token": request.Token,
+	})
+}
+
+func resendVerificationEmail(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.ResendVerificationEmail(claim.UserId); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func editUserProfile(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.ProfileEditRequest
+
+	request.FirstName = ctx.Request.FormValue("first_name")
+	request.LastName = ctx.Request.FormValue("last_name")
+	request.CountryCode = ctx.Request.FormValue("country_code")
+	request.PhoneNumber = ctx.Request.FormValue("phone_number")
+	request.Locale = ctx.Request.FormValue("locale")
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	avatar, err := ctx.FormFile("avatar")
+	if avatar != nil && err != nil {
+		if err.Error() != "multipart: no multipart data" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": []string{err.Error()},
+			})
+			return
+		}
+	}
+
+	if avatar != nil && !isValidImageFormat(avatar.Filename) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{"Invalid file format."},
+		})
+		return
+	}
+
+	if errors := interactor.EditProfile(claim.UserId, request, avatar); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func updatePassword(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.UpdatePasswordRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.UpdatePassword(claim.UserId, request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	user, errors := interactor.GetUser(claim.UserId)
+	if errors != nil {
+		ctx.Status(http.StatusOK)
+		return
+	}
+
+	token, err := JWTAuthorization(user)
+	if err != nil {
+		ctx.Status(http.StatusOK)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"access_token": token})
+}
+
+func requestEmailChange(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.ChangeEmailRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.RequestEmailChange(claim.UserId, request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func confirmEmailChange(ctx *gin.Context) {
+	var request carwise.ConfirmEmailChangeRequest
+
+	err := ctx.ShouldBind(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ConfirmEmailChange(request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func confirmEmailChangePage(ctx *gin.Context) {
+	var request carwise.ConfirmEmailChangeRequest
+
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	renderConfirmationPage(ctx, "Confirm email change", map[string]string{
+		"user_id": request.UserID,
+		"token":   request.Token,
+	})
+}
+
+func sendPhoneVerification(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.SendPhoneVerification(claim.UserId); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func verifyPhone(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.PhoneVerifyRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.VerifyPhone(claim.UserId, request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func setupTwoFactor(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.SetupTwoFactor(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	qrCode, err := qrCodePNG(response.OTPAuthURI)
+	if err != nil {
+		log.Println("Error generating QR code:", err)
+	} else {
+		response.QRCode = "data:image/png;base64," + qrCode
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func confirmTwoFactor(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.TwoFactorCodeRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.ConfirmTwoFactor(claim.UserId, request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func disableTwoFactor(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.TwoFactorDisableRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.DisableTwoFactor(claim.UserId, request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func getBrands(ctx *gin.Context) {
+	brands, err := interactor.GetBrands()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, brands)
+}
+
+func updatePrivacySettings(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.PrivacySettingsRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.UpdatePrivacySettings(claim.UserId, request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func publicProfile(ctx *gin.Context) {
+	profile, errors := interactor.GetPublicProfile(ctx.Param("id"))
+	if errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, profile)
+}
+
+func sellerCars(ctx *gin.Context) {
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.ListSellerCars(viewerID(ctx), ctx.Param("id"), page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func myCars(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.ListMyCars(claim.UserId, ctx.Query("status"))
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func bulkUpdateMyCars(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.BulkListingActionRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.BulkUpdateListings(claim.UserId, request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func revealContact(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.RevealContact(claim.UserId, ctx.Param("id"), ctx.ClientIP())
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func listCars(ctx *gin.Context) {
+	brandIDStr := ctx.DefaultQuery("brand_id", "0")
+	seriesIDStr := ctx.DefaultQuery("series_id", "0")
+	modelIDStr := ctx.DefaultQuery("model_id", "0")
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	brandID, err := strconv.Atoi(brandIDStr)
+	if err != nil {
+		log.Println("Error converting brand_id:", err)
+		ctx.JSON(400, gin.H{"error": "Invalid brand_id"})
+		return
+	}
+
+	seriesID, err := strconv.Atoi(seriesIDStr)
+	if err != nil {
+		log.Println("Error converting series_id:", err)
+		ctx.JSON(400, gin.H{"error": "Invalid series_id"})
+		return
+	}
+
+	modelID, err := strconv.Atoi(modelIDStr)
+	if err != nil {
+		log.Println("Error converting model_id:", err)
+		ctx.JSON(400, gin.H{"error": "Invalid model_id"})
+		return
+	}
+
+	response, errors := interactor.ListCars(viewerID(ctx), page, limit, brandID, seriesID, modelID)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+
+}
+func getCarByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	carDetail, err := interactor.GetCarDetail(viewerID(ctx), id)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, carDetail)
+
+}
+
+func listSavedSearches(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.ListSavedSearches(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func createSavedSearch(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.SavedSearchRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.CreateSavedSearch(claim.UserId, request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func updateSavedSearch(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.SavedSearchRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.UpdateSavedSearch(claim.UserId, ctx.Param("id"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func deleteSavedSearch(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.DeleteSavedSearch(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func savedSearchCars(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.RunSavedSearch(claim.UserId, ctx.Param("id"), page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func unsubscribeSavedSearch(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{"Token is required."},
+		})
+		return
+	}
+
+	if errors := interactor.UnsubscribeSavedSearch(token); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func addFavorite(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.AddFavorite(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func removeFavorite(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.RemoveFavorite(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func myFavorites(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.ListFavorites(claim.UserId, page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func viewerID(ctx *gin.Context) string {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		return ""
+	}
+	return userContext.(*UserClaims).UserId
+}
+func createCar(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.CarCreateRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+	if errors := interactor.CreateCar(claim.UserId, request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+
+}
+func updateCar(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.CarCreateRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.UpdateCar(claim.UserId, ctx.Param("id"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+func deleteCar(c *gin.Context) {
+
+}
+
+func predictPrice(c *gin.Context) {
+
+}
+func getPredictionHistory(c *gin.Context) {
+
+}
+func suggestCar(c *gin.Context) {
+
+}
+func getSuggestionHistory(c *gin.Context) {
+
+}
+
+func adminPermissions(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"role":        claim.Role,
+		"permissions": carwise.RolePermissions[claim.Role],
+	})
+}
+
+func adminSearchUsers(ctx *gin.Context) {
+	query := ctx.Query("q")
+	role := ctx.Query("role")
+	status := ctx.Query("status")
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.SearchUsers(query, role, status, page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func adminSearchLoginEvents(ctx *gin.Context) {
+	query := ctx.Query("q")
+	userId := ctx.Query("user_id")
+	outcome := ctx.Query("outcome")
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.SearchLoginEvents(query, userId, outcome, page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func adminGetUser(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	user, errors := interactor.GetUserForAdmin(id)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
+
+func adminChangeUserStatus(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.UserStatusChangeRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ChangeUserStatus(claim.UserId, claim.Role, ctx.Param("id"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func adminModerateCar(ctx *gin.Context) {
+	var request carwise.ListingModerationRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ModerateListing(ctx.Param("id"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func adminListEmailTemplates(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, interactor.GetEmailTemplates())
+}
+
+func adminPreviewEmail(ctx *gin.Context) {
+	preview, errors := interactor.PreviewEmail(ctx.Param("template"), ctx.Query("locale"))
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	switch ctx.Query("format") {
+	case "html":
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(preview.HTML))
+	case "text":
+		ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(preview.Text))
+	default:
+		ctx.JSON(http.StatusOK, preview)
+	}
+}
+
+func adminChangeUserRole(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.UserRoleChangeRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ChangeUserRole(claim.UserId, ctx.Param("id"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func createOrganization(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.OrganizationRequest
+
+	request.Name = ctx.Request.FormValue("name")
+	request.TaxNumber = ctx.Request.FormValue("tax_number")
+	request.TaxOffice = ctx.Request.FormValue("tax_office")
+	request.Address = ctx.Request.FormValue("address")
+	request.City = ctx.Request.FormValue("city")
+	request.CountryCode = ctx.Request.FormValue("country_code")
+	request.PhoneNumber = ctx.Request.FormValue("phone_number")
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	logo, err := ctx.FormFile("logo")
+	if logo != nil && err != nil {
+		if err.Error() != "multipart: no multipart data" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": []string{err.Error()},
+			})
+			return
+		}
+	}
+
+	if logo != nil && !isValidImageFormat(logo.Filename) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{"Invalid file format."},
+		})
+		return
+	}
+
+	response, errors := interactor.CreateOrganization(claim.UserId, request, logo)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func updateOrganization(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.OrganizationRequest
+
+	request.Name = ctx.Request.FormValue("name")
+	request.TaxNumber = ctx.Request.FormValue("tax_number")
+	request.TaxOffice = ctx.Request.FormValue("tax_office")
+	request.Address = ctx.Request.FormValue("address")
+	request.City = ctx.Request.FormValue("city")
+	request.CountryCode = ctx.Request.FormValue("country_code")
+	request.PhoneNumber = ctx.Request.FormValue("phone_number")
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	logo, err := ctx.FormFile("logo")
+	if logo != nil && err != nil {
+		if err.Error() != "multipart: no multipart data" {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": []string{err.Error()},
+			})
+			return
+		}
+	}
+
+	if logo != nil && !isValidImageFormat(logo.Filename) {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{"Invalid file format."},
+		})
+		return
+	}
+
+	if errors := interactor.UpdateOrganization(claim.UserId, ctx.Param("id"), request, logo); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func getOrganization(ctx *gin.Context) {
+	response, errors := interactor.GetOrganization(ctx.Param("id"))
+	if errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func organizationCars(ctx *gin.Context) {
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.ListOrganizationCars(viewerID(ctx), ctx.Param("id"), page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func myOrganizations(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetMyOrganizations(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func organizationMembers(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetOrganizationMembers(claim.UserId, ctx.Param("id"))
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func inviteOrganizationMember(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.OrganizationMemberRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.InviteOrganizationMember(claim.UserId, ctx.Param("id"), request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func organizationInvitations(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetOrganizationInvitations(claim.UserId, ctx.Param("id"))
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func cancelOrganizationInvitation(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.CancelOrganizationInvitation(claim.UserId, ctx.Param("id"), ctx.Param("invitationId")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func myInvitations(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetMyInvitations(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func acceptInvitation(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.AcceptOrganizationInvitation(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func declineInvitation(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.DeclineOrganizationInvitation(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func changeOrganizationMemberRole(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.OrganizationMemberRoleRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.ChangeOrganizationMemberRole(claim.UserId, ctx.Param("id"), ctx.Param("userId"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func removeOrganizationMember(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.RemoveOrganizationMember(claim.UserId, ctx.Param("id"), ctx.Param("userId")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func importListings(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	feed, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{"A feed file is required."},
+		})
+		return
+	}
+	if feed.Size > maxFeedSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": []string{"Feed files are limited to 10 MB."},
+		})
+		return
+	}
+
+	format := strings.ToLower(ctx.PostForm("format"))
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(feed.Filename)), ".")
+	}
+
+	fullSync := false
+	if value := ctx.PostForm("full_sync"); value != "" {
+		fullSync, err = strconv.ParseBool(value)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{
+				"error": []string{"Invalid full_sync value."},
+			})
+			return
+		}
+	}
+
+	file, err := feed.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(io.LimitReader(file, maxFeedSize))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	response, errors := interactor.StartListingImport(claim.UserId, ctx.Param("id"), format, fullSync, content)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response)
+}
+
+func listingImports(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.ListListingImports(claim.UserId, ctx.Param("id"), page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func listingImport(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetListingImport(claim.UserId, ctx.Param("id"), ctx.Param("jobId"))
+	if errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func adminSearchOrganizations(ctx *gin.Context) {
+	query := ctx.Query("q")
+	verification := ctx.Query("verification")
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.SearchOrganizations(query, verification, page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func adminVerifyOrganization(ctx *gin.Context) {
+	var request carwise.OrganizationVerifyRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	if errors := interactor.VerifyOrganization(ctx.Param("id"), request); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func listConversations(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.ListConversations(claim.UserId, page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func startConversation(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.ConversationCreateRequest
+
+	request.CarID = ctx.Request.FormValue("car_id")
+	request.Body = ctx.Request.FormValue("body")
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	attachments, errors := messageAttachments(ctx)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	response, errors := interactor.StartConversation(claim.UserId, request, attachments)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func unreadMessageCount(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetUnreadMessageCount(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func conversationMessages(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 50)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.GetMessages(claim.UserId, ctx.Param("id"), page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func sendMessage(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.MessageRequest
+
+	request.Body = ctx.Request.FormValue("body")
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	attachments, errors := messageAttachments(ctx)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	response, errors := interactor.SendMessage(claim.UserId, ctx.Param("id"), request, attachments)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+func messageAttachment(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	attachment, errors := interactor.OpenMessageAttachment(claim.UserId, ctx.Param("id"), ctx.Param("file"))
+	if errors != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error": errors,
+		})
+		return
+	}
+	if attachment == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": []string{"attachment not found"}})
+		return
+	}
+	defer attachment.Close()
+
+	content, err := io.ReadAll(attachment)
+	if err != nil {
+		log.Printf("Error reading attachment: %v\n", err)
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": []string{"failed to read attachment"}})
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=86400")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.Data(http.StatusOK, http.DetectContentType(content), content)
+}
+
+func blockConversation(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.BlockConversation(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func unblockConversation(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.UnblockConversation(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func createOffer(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
 
+	var request carwise.OfferRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.CreateOffer(claim.UserId, ctx.Param("id"), request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response)
 }
-func getSuggestionHistory(c *gin.Context) {
 
+func listOffers(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	response, errors := interactor.ListOffers(claim.UserId, ctx.Query("role"), ctx.Query("status"), page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func getOffer(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetOffer(claim.UserId, ctx.Param("id"))
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func respondToOffer(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.OfferActionRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.RespondToOffer(claim.UserId, ctx.Param("id"), request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func listNotifications(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	page, limit, ok := parsePagination(ctx, 20)
+	if !ok {
+		return
+	}
+
+	unreadOnly := ctx.Query("unread") == "true"
+
+	response, errors := interactor.ListNotifications(claim.UserId, unreadOnly, page, limit)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func markNotificationRead(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.MarkNotificationRead(claim.UserId, ctx.Param("id")); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func markAllNotificationsRead(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	if errors := interactor.MarkAllNotificationsRead(claim.UserId); errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
+func notificationPreferences(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	response, errors := interactor.GetNotificationPreferences(claim.UserId)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func updateNotificationPreferences(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	var request carwise.NotificationPreferencesRequest
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": []string{err.Error()},
+		})
+		return
+	}
+
+	if err := ValidateStruct(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": err,
+		})
+		return
+	}
+
+	response, errors := interactor.UpdateNotificationPreferences(claim.UserId, request)
+	if errors != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{
+			"error": errors,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+func issueEventTicket(ctx *gin.Context) {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+	claim := userContext.(*UserClaims)
+
+	ticket, errors := interactor.IssueEventTicket(claim.UserId, ctx.GetString("token"), claim.IssuedAt)
+	if errors != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": errors})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, ticket)
+}
+
+func streamEvents(ctx *gin.Context) {
+	session, exists := eventSession(ctx)
+	if !exists {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+		return
+	}
+
+	events, unsubscribe := interactor.SubscribeEvents(session.UserID)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.SSEvent("ready", gin.H{"user_id": session.UserID})
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			ctx.SSEvent(event.Type, event.Data)
+			return true
+		case <-heartbeat.C:
+			// Re-check the session so a logout, revocation or ban also
+			// closes streams that are already open. A session that cannot
+			// be checked is closed too; the client reconnects.
+			active, errors := interactor.IsEventSessionActive(session)
+			if errors != nil || !active {
+				return false
+			}
+			ctx.SSEvent("ping", gin.H{"time": time.Now()})
+			return true
+		case <-ctx.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+func eventSession(ctx *gin.Context) (*carwise.EventSession, bool) {
+	if session, exists := ctx.Get("eventSession"); exists {
+		return session.(*carwise.EventSession), true
+	}
+
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		return nil, false
+	}
+	claim := userContext.(*UserClaims)
+
+	return &carwise.EventSession{
+		UserID:   claim.UserId,
+		Token:    ctx.GetString("token"),
+		IssuedAt: claim.IssuedAt,
+	}, true
+}
+
+func messageAttachments(ctx *gin.Context) ([]*multipart.FileHeader, []string) {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		if err == http.ErrNotMultipart {
+			return nil, nil
+		}
+		return nil, []string{err.Error()}
+	}
+
+	attachments := form.File["attachments"]
+	for _, attachment := range attachments {
+		if attachment.Size > maxAttachmentSize {
+			return nil, []string{"Attachments are limited to 5 MB each."}
+		}
+		if !isValidImageFormat(attachment.Filename) || !hasImageContent(attachment) {
+			return nil, []string{"Invalid file format."}
+		}
+	}
+
+	return attachments, nil
+}
+
+// hasImageContent sniffs the start of an upload, since the file name alone
+// says nothing about what was actually sent.
+func hasImageContent(header *multipart.FileHeader) bool {
+	file, err := header.Open()
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return false
+	}
+
+	switch http.DetectContentType(head[:n]) {
+	case "image/jpeg", "image/png":
+		return true
+	}
+	return false
 }
 
 func isValidImageFormat(filename string) bool {
@@ -363,3 +2706,22 @@ func isValidImageFormat(filename string) bool {
 	}
 	return false
 }
+
+func qrCodePNG(content string) (string, error) {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return "", err
+	}
+
+	code, err = barcode.Scale(code, 256, 256)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, code); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestParsePagination(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantOK    bool
+		wantPage  int
+		wantLimit int
+	}{
+		{name: "defaults", query: "", wantOK: true, wantPage: 1, wantLimit: 20},
+		{name: "explicit", query: "?page=3&limit=50", wantOK: true, wantPage: 3, wantLimit: 50},
+		{name: "maximum limit", query: "?limit=100", wantOK: true, wantPage: 1, wantLimit: 100},
+		{name: "zero page", query: "?page=0"},
+		{name: "negative page", query: "?page=-1"},
+		{name: "zero limit", query: "?limit=0"},
+		{name: "limit too large", query: "?limit=101"},
+		{name: "non numeric page", query: "?page=first"},
+		{name: "non numeric limit", query: "?limit=all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/list"+tt.query, nil)
+
+			page, limit, ok := parsePagination(ctx, 20)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok=%v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if recorder.Code != http.StatusBadRequest {
+					t.Errorf("got status %d, want %d", recorder.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if page != tt.wantPage || limit != tt.wantLimit {
+				t.Errorf("got page %d limit %d, want %d %d", page, limit, tt.wantPage, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestVerifyEmailPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+	}{
+		{name: "valid link", query: "?email=jane%40example.com&token=abc%22def", wantStatus: http.StatusOK},
+		{name: "missing token", query: "?email=jane%40example.com", wantStatus: http.StatusBadRequest},
+		{name: "invalid email", query: "?email=jane&token=abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/auth/verify-email"+tt.query, nil)
+
+			// The interactor is not set up, so the page cannot verify anything.
+			verifyEmailPage(ctx)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("got status %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			body := recorder.Body.String()
+			for _, want := range []string{
+				`<form method="post" action="/auth/verify-email">`,
+				`name="email" value="jane@example.com"`,
+				`name="token" value="abc&#34;def"`,
+			} {
+				if !strings.Contains(body, want) {
+					t.Errorf("page does not contain %q:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageAttachments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	pngHeader := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	tests := []struct {
+		name     string
+		filename string
+		content  []byte
+		wantErr  bool
+	}{
+		{name: "png", filename: "photo.png", content: pngHeader},
+		{name: "jpeg", filename: "photo.JPG", content: []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")},
+		{name: "html renamed to png", filename: "photo.png", content: []byte("<html><script>alert(1)</script></html>"), wantErr: true},
+		{name: "wrong extension", filename: "photo.gif", content: pngHeader, wantErr: true},
+		{name: "too large", filename: "photo.png", content: append(pngHeader, make([]byte, maxAttachmentSize)...), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			part, err := writer.CreateFormFile("attachments", tt.filename)
+			if err != nil {
+				t.Fatalf("CreateFormFile: %v", err)
+			}
+			part.Write(tt.content)
+			writer.Close()
+
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/conversations", &body)
+			ctx.Request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			attachments, errors := messageAttachments(ctx)
+			if tt.wantErr {
+				if errors == nil {
+					t.Fatalf("got %d attachments, want an error", len(attachments))
+				}
+				return
+			}
+			if errors != nil || len(attachments) != 1 {
+				t.Fatalf("got %d attachments and errors %v, want one attachment", len(attachments), errors)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ go 1.21.0
 require github.com/gin-gonic/gin v1.10.0
 
 require (
+	github.com/boombuler/barcode v1.0.1
 	github.com/bytedance/sonic v1.11.6 // indirect
 	github.com/bytedance/sonic/loader v0.1.1 // indirect
 	github.com/cloudwego/base64x v0.1.4 // indirect
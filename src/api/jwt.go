@@ -50,8 +50,46 @@ func JWTAuthorization(user *carwise.User) (string, error) {
 }
 
 func AuthMiddleware() gin.HandlerFunc {
+	return authenticate(true)
+}
+
+// OptionalAuthMiddleware lets anonymous requests through but still rejects a
+// malformed or revoked token, so clients notice they need to sign in again.
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	return authenticate(false)
+}
+
+// EventStreamAuth accepts either the usual bearer header or a single-use
+// ticket from POST /events/ticket, since browser EventSource clients cannot
+// set request headers and a bearer token in the URL would end up in logs.
+func EventStreamAuth() gin.HandlerFunc {
+	bearer := AuthMiddleware()
+	return func(ctx *gin.Context) {
+		ticket := ctx.Query("ticket")
+		if ticket == "" {
+			bearer(ctx)
+			return
+		}
+
+		session, errorMessages := interactor.RedeemEventTicket(ticket)
+		if errorMessages != nil {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": errorMessages})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("eventSession", session)
+		ctx.Next()
+	}
+}
+
+func authenticate(required bool) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
+		if authHeader == "" && !required {
+			ctx.Next()
+			return
+		}
 		if authHeader == "" {
 			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
 			ctx.Abort()
@@ -96,8 +134,65 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// A revocation that cannot be checked must not let the token through.
+		isRevoked, errorMessages := interactor.IsTokenRevoked(claims.UserId, claims.IssuedAt)
+		if errorMessages != nil {
+			ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": errorMessages})
+			ctx.Abort()
+			return
+		}
+		if isRevoked {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+			ctx.Abort()
+			return
+		}
+
 		ctx.Set("user", claims)
 		ctx.Set("token", tokenString)
 		ctx.Next()
 	}
 }
+
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userContext, exists := ctx.Get("user")
+		if !exists {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+			ctx.Abort()
+			return
+		}
+		claim := userContext.(*UserClaims)
+
+		for _, role := range roles {
+			if claim.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Insufficient role"})
+		ctx.Abort()
+	}
+}
+
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userContext, exists := ctx.Get("user")
+		if !exists {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "No User found in request context"})
+			ctx.Abort()
+			return
+		}
+		claim := userContext.(*UserClaims)
+
+		for _, permission := range permissions {
+			if !carwise.HasPermission(claim.Role, permission) {
+				ctx.JSON(http.StatusForbidden, gin.H{"error": "Missing permission: " + permission})
+				ctx.Abort()
+				return
+			}
+		}
+
+		ctx.Next()
+	}
+}
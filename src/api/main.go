@@ -5,6 +5,7 @@ import (
 	"infra"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -20,42 +21,235 @@ func main() {
 	}
 
 	app := gin.Default()
+	if proxies := strings.TrimSpace(os.Getenv("TRUSTED_PROXIES")); proxies != "" {
+		if err := app.SetTrustedProxies(strings.Split(proxies, ",")); err != nil {
+			log.Fatal(err)
+		}
+	} else if err := app.SetTrustedProxies(nil); err != nil {
+		log.Fatal(err)
+	}
 	app.Static("/images", "./images")
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"*"},
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "RateLimit-Policy", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
+	frontendURL := strings.TrimRight(os.Getenv("FRONTEND_URL"), "/")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+
+	apiURL := strings.TrimRight(os.Getenv("API_URL"), "/")
+	if apiURL == "" {
+		apiURL = "http://localhost:8080"
+	}
+
+	// Verification codes are only written to the log during development.
+	var smsGW carwise.SMSGateway
+	if os.Getenv("APP_ENV") == "development" {
+		smsGW = infra.NewLogSMSGateway()
+	} else {
+		twilioGW := infra.NewTwilioSMSGateway()
+		if !twilioGW.Configured() {
+			log.Fatal("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER must be set outside development")
+		}
+		smsGW = twilioGW
+	}
+
+	var phoneRelayGW carwise.PhoneRelayGateway
+	if os.Getenv("PHONE_RELAY_NUMBER") != "" {
+		phoneRelayGW = infra.NewPhoneRelayGateway()
+	}
+
 	interactor = carwise.NewInteractor(
 		carwise.Services{
-			UserRepo:          infra.NewUserRepository(),
-			TokenRepo:         infra.NewTokenRepository(),
-			AuxRepo:           infra.NewAuxiliaryRepository(),
-			MailGW:            infra.NewMailGateway(),
-			PasswordResetRepo: infra.NewPasswordResetRepository(),
-			CDNRepo:           infra.NewCDNRepository(),
-			CarRepo:           infra.NewCarRepository(),
+			UserRepo:              infra.NewUserRepository(),
+			TokenRepo:             infra.NewTokenRepository(),
+			AuxRepo:               infra.NewAuxiliaryRepository(),
+			MailGW:                infra.NewMailGateway(),
+			EmailRenderer:         infra.NewEmailRenderer(),
+			SMSGW:                 smsGW,
+			PhoneRelayGW:          phoneRelayGW,
+			PasswordResetRepo:     infra.NewPasswordResetRepository(),
+			EmailVerificationRepo: infra.NewEmailVerificationRepository(),
+			EmailChangeRepo:       infra.NewEmailChangeRepository(),
+			AccountDeletionRepo:   infra.NewAccountDeletionRepository(),
+			IdentityProviders:     infra.NewIdentityProviders(frontendURL),
+			UserIdentityRepo:      infra.NewUserIdentityRepository(),
+			OAuthStateRepo:        infra.NewOAuthStateRepository(),
+			PhoneVerificationRepo: infra.NewPhoneVerificationRepository(),
+			RecoveryCodeRepo:      infra.NewRecoveryCodeRepository(),
+			TwoFactorRepo:         infra.NewTwoFactorChallengeRepository(),
+			LoginAttemptRepo:      infra.NewLoginAttemptRepository(),
+			LoginEventRepo:        infra.NewLoginEventRepository(),
+			RateLimitRepo:         infra.NewRateLimitRepository(),
+			CDNRepo:               infra.NewCDNRepository(),
+			CarRepo:               infra.NewCarRepository(),
+			ContactRevealRepo:     infra.NewContactRevealRepository(),
+			OrganizationRepo:      infra.NewOrganizationRepository(),
+			ImportJobRepo:         infra.NewImportJobRepository(),
+			FavoriteRepo:          infra.NewFavoriteRepository(),
+			SavedSearchRepo:       infra.NewSavedSearchRepository(),
+			PriceHistoryRepo:      infra.NewPriceHistoryRepository(),
+			ConversationRepo:      infra.NewConversationRepository(),
+			UserBlockRepo:         infra.NewUserBlockRepository(),
+			OfferRepo:             infra.NewOfferRepository(),
+			NotificationRepo:      infra.NewNotificationRepository(),
+			EventBroker:           infra.NewEventBroker(),
+			FeedParsers:           infra.NewListingFeedParsers(),
+			ValidateRequest:       ValidateStruct,
+		},
+		carwise.Config{
+			FrontendURL: frontendURL,
+			APIURL:      apiURL,
 		},
 	)
 
+	go func() {
+		for {
+			interactor.PurgeDeletedAccounts()
+			interactor.RemindExpiringListings()
+			interactor.ExpireListings()
+			interactor.FailStaleImports()
+			interactor.ExpireOffers()
+			time.Sleep(time.Hour)
+		}
+	}()
+
+	go func() {
+		for {
+			interactor.SendSearchAlerts()
+			interactor.SendMessageNotifications()
+			time.Sleep(5 * time.Minute)
+		}
+	}()
+
+	app.Use(RateLimit(NewRateLimitPolicy("global", 300, time.Minute), RateLimitByIP))
+
+	registerLimit := RateLimit(NewRateLimitPolicy("register", 10, time.Hour), RateLimitByIP)
+	loginLimit := RateLimit(NewRateLimitPolicy("login", 20, 15*time.Minute), RateLimitByIP)
+	resetPasswordIPLimit := RateLimit(NewRateLimitPolicy("reset-password-ip", 10, time.Hour), RateLimitByIP)
+	resetPasswordEmailLimit := RateLimit(NewRateLimitPolicy("reset-password-email", 3, time.Hour), RateLimitByEmail)
+	verifyEmailLimit := RateLimit(NewRateLimitPolicy("verify-email", 10, time.Hour), RateLimitByIP)
+	sendCodeLimit := RateLimit(NewRateLimitPolicy("send-code", 5, time.Hour), RateLimitByUser)
+	contactRevealLimit := RateLimit(NewRateLimitPolicy("contact-reveal", 20, time.Hour), RateLimitByUser)
+	exportLimit := RateLimit(NewRateLimitPolicy("export", 5, time.Hour), RateLimitByUser)
+	importLimit := RateLimit(NewRateLimitPolicy("import", 10, time.Hour), RateLimitByUser)
+	messageLimit := RateLimit(NewRateLimitPolicy("message", 60, time.Hour), RateLimitByUser)
+	offerLimit := RateLimit(NewRateLimitPolicy("offer", 20, time.Hour), RateLimitByUser)
+
 	auth := app.Group("/auth")
 	{
-		auth.POST("/register", registerUser)
-		auth.POST("/login", loginUser)
+		auth.POST("/register", registerLimit, registerUser)
+		auth.POST("/login", loginLimit, loginUser)
+		auth.POST("/login/2fa", loginLimit, loginTwoFactor)
+		auth.GET("/oauth/:provider", loginLimit, startSocialLogin)
+		auth.GET("/oauth/:provider/callback", loginLimit, socialLoginCallback)
+		auth.POST("/oauth/:provider/callback", loginLimit, socialLoginCallback)
 		auth.POST("/logout", AuthMiddleware(), logoutUser)
-		auth.POST("/reset-password", resetPasswordRequest)
-		auth.PUT("/reset-password", resetPassword)
+		auth.POST("/reset-password", resetPasswordIPLimit, resetPasswordEmailLimit, resetPasswordRequest)
+		auth.PUT("/reset-password", resetPasswordIPLimit, resetPassword)
+		auth.GET("/verify-email", verifyEmailLimit, verifyEmailPage)
+		auth.POST("/verify-email", verifyEmailLimit, verifyEmail)
+		auth.POST("/verify-email/resend", AuthMiddleware(), sendCodeLimit, resendVerificationEmail)
+		auth.GET("/email-change/confirm", verifyEmailLimit, confirmEmailChangePage)
+		auth.POST("/email-change/confirm", verifyEmailLimit, confirmEmailChange)
 	}
 
 	profile := app.Group("/profile")
 	{
 		profile.GET("/", AuthMiddleware(), userProfile)
 		profile.PUT("/edit", AuthMiddleware(), editUserProfile)
+		profile.PUT("/privacy", AuthMiddleware(), updatePrivacySettings)
+		profile.DELETE("/", AuthMiddleware(), deleteProfile)
+		profile.POST("/delete/send-code", AuthMiddleware(), sendCodeLimit, sendAccountDeletionCode)
+		profile.POST("/cancel-deletion", AuthMiddleware(), cancelProfileDeletion)
+		profile.GET("/security-events", AuthMiddleware(), securityEvents)
+		profile.GET("/cars", AuthMiddleware(), myCars)
+		profile.POST("/cars/bulk", AuthMiddleware(), bulkUpdateMyCars)
+		profile.GET("/organizations", AuthMiddleware(), myOrganizations)
+		profile.GET("/invitations", AuthMiddleware(), myInvitations)
+		profile.POST("/invitations/:id/accept", AuthMiddleware(), acceptInvitation)
+		profile.POST("/invitations/:id/decline", AuthMiddleware(), declineInvitation)
+		profile.GET("/favorites", AuthMiddleware(), myFavorites)
+		profile.GET("/export", AuthMiddleware(), exportLimit, exportProfile)
+		profile.PUT("/password", AuthMiddleware(), updatePassword)
+		profile.POST("/email", AuthMiddleware(), sendCodeLimit, requestEmailChange)
+		profile.POST("/phone/send-code", AuthMiddleware(), sendCodeLimit, sendPhoneVerification)
+		profile.POST("/phone/verify", AuthMiddleware(), verifyPhone)
+		profile.POST("/2fa/setup", AuthMiddleware(), setupTwoFactor)
+		profile.POST("/2fa/confirm", AuthMiddleware(), confirmTwoFactor)
+		profile.POST("/2fa/disable", AuthMiddleware(), disableTwoFactor)
+	}
+
+	users := app.Group("/users")
+	{
+		users.GET("/:id/public", publicProfile)
+		users.GET("/:id/cars", OptionalAuthMiddleware(), sellerCars)
+	}
+
+	organizations := app.Group("/organizations")
+	{
+		organizations.POST("/", AuthMiddleware(), createOrganization)
+		organizations.GET("/:id", getOrganization)
+		organizations.PUT("/:id", AuthMiddleware(), updateOrganization)
+		organizations.GET("/:id/cars", OptionalAuthMiddleware(), organizationCars)
+		organizations.GET("/:id/members", AuthMiddleware(), organizationMembers)
+		organizations.PUT("/:id/members/:userId", AuthMiddleware(), changeOrganizationMemberRole)
+		organizations.DELETE("/:id/members/:userId", AuthMiddleware(), removeOrganizationMember)
+		organizations.POST("/:id/invitations", AuthMiddleware(), inviteOrganizationMember)
+		organizations.GET("/:id/invitations", AuthMiddleware(), organizationInvitations)
+		organizations.DELETE("/:id/invitations/:invitationId", AuthMiddleware(), cancelOrganizationInvitation)
+		organizations.POST("/:id/imports", AuthMiddleware(), importLimit, importListings)
+		organizations.GET("/:id/imports", AuthMiddleware(), listingImports)
+		organizations.GET("/:id/imports/:jobId", AuthMiddleware(), listingImport)
+	}
+
+	savedSearches := app.Group("/saved-searches")
+	{
+		savedSearches.GET("/", AuthMiddleware(), listSavedSearches)
+		savedSearches.POST("/", AuthMiddleware(), createSavedSearch)
+		savedSearches.PUT("/:id", AuthMiddleware(), updateSavedSearch)
+		savedSearches.DELETE("/:id", AuthMiddleware(), deleteSavedSearch)
+		savedSearches.GET("/:id/cars", AuthMiddleware(), savedSearchCars)
+		savedSearches.GET("/unsubscribe", verifyEmailLimit, unsubscribeSavedSearch)
+		savedSearches.POST("/unsubscribe", verifyEmailLimit, unsubscribeSavedSearch)
+	}
+
+	app.POST("/events/ticket", AuthMiddleware(), issueEventTicket)
+	app.GET("/events", EventStreamAuth(), streamEvents)
+
+	conversations := app.Group("/conversations")
+	{
+		conversations.GET("/", AuthMiddleware(), listConversations)
+		conversations.POST("/", AuthMiddleware(), messageLimit, startConversation)
+		conversations.GET("/unread", AuthMiddleware(), unreadMessageCount)
+		conversations.GET("/:id/messages", AuthMiddleware(), conversationMessages)
+		conversations.POST("/:id/messages", AuthMiddleware(), messageLimit, sendMessage)
+		conversations.GET("/:id/attachments/:file", AuthMiddleware(), messageAttachment)
+		conversations.POST("/:id/block", AuthMiddleware(), blockConversation)
+		conversations.DELETE("/:id/block", AuthMiddleware(), unblockConversation)
+	}
+
+	notifications := app.Group("/notifications")
+	{
+		notifications.GET("/", AuthMiddleware(), listNotifications)
+		notifications.POST("/read", AuthMiddleware(), markAllNotificationsRead)
+		notifications.POST("/:id/read", AuthMiddleware(), markNotificationRead)
+		notifications.GET("/preferences", AuthMiddleware(), notificationPreferences)
+		notifications.PUT("/preferences", AuthMiddleware(), updateNotificationPreferences)
+	}
+
+	offers := app.Group("/offers")
+	{
+		offers.GET("/", AuthMiddleware(), listOffers)
+		offers.GET("/:id", AuthMiddleware(), getOffer)
+		offers.PUT("/:id", AuthMiddleware(), offerLimit, respondToOffer)
 	}
 
 	aux := app.Group("/aux")
@@ -65,8 +259,12 @@ func main() {
 
 	cars := app.Group("/cars")
 	{
-		cars.GET("/", listCars)
-		cars.GET("/:id", getCarByID)
+		cars.GET("/", OptionalAuthMiddleware(), listCars)
+		cars.GET("/:id", OptionalAuthMiddleware(), getCarByID)
+		cars.POST("/:id/favorite", AuthMiddleware(), addFavorite)
+		cars.DELETE("/:id/favorite", AuthMiddleware(), removeFavorite)
+		cars.POST("/:id/contact-reveal", AuthMiddleware(), contactRevealLimit, revealContact)
+		cars.POST("/:id/offers", AuthMiddleware(), offerLimit, createOffer)
 		cars.POST("/", AuthMiddleware(), createCar)
 		cars.PUT("/:id", AuthMiddleware(), updateCar)
 		cars.DELETE("/:id", AuthMiddleware(), deleteCar)
@@ -80,5 +278,20 @@ func main() {
 		model.GET("/suggestions/history", AuthMiddleware(), getSuggestionHistory)
 	}
 
+	admin := app.Group("/admin", AuthMiddleware(), RequireRole(carwise.UserRoleAdmin, carwise.UserRoleModerator, carwise.UserRoleCatalogEditor))
+	{
+		admin.GET("/permissions", adminPermissions)
+		admin.GET("/users", RequirePermission(carwise.PermissionUsersView), adminSearchUsers)
+		admin.GET("/users/:id", RequirePermission(carwise.PermissionUsersView), adminGetUser)
+		admin.GET("/login-events", RequirePermission(carwise.PermissionUsersView), adminSearchLoginEvents)
+		admin.PUT("/users/:id/status", RequirePermission(carwise.PermissionUsersBan), adminChangeUserStatus)
+		admin.PUT("/users/:id/role", RequirePermission(carwise.PermissionUsersRole), adminChangeUserRole)
+		admin.PUT("/cars/:id/moderation", RequirePermission(carwise.PermissionCarsModerate), adminModerateCar)
+		admin.GET("/organizations", RequirePermission(carwise.PermissionOrganizationsVerify), adminSearchOrganizations)
+		admin.PUT("/organizations/:id/verify", RequirePermission(carwise.PermissionOrganizationsVerify), adminVerifyOrganization)
+		admin.GET("/emails", RequirePermission(carwise.PermissionEmailsPreview), adminListEmailTemplates)
+		admin.GET("/emails/:template/preview", RequirePermission(carwise.PermissionEmailsPreview), adminPreviewEmail)
+	}
+
 	app.Run(os.Getenv("HOST") + ":" + os.Getenv("PORT"))
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"carwise"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RateLimitKeyFunc func(ctx *gin.Context) string
+
+func NewRateLimitPolicy(name string, limit int, window time.Duration) carwise.RateLimitPolicy {
+	policy := carwise.RateLimitPolicy{
+		Name:   name,
+		Limit:  limit,
+		Window: window,
+	}
+
+	envName := "RATE_LIMIT_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	override := strings.TrimSpace(os.Getenv(envName))
+	if override == "" {
+		return policy
+	}
+
+	limitStr, windowStr, found := strings.Cut(override, "/")
+	overrideLimit, err := strconv.Atoi(limitStr)
+	if !found || err != nil || overrideLimit <= 0 {
+		log.Printf("Ignoring invalid %s=%q, expected <limit>/<window>", envName, override)
+		return policy
+	}
+	overrideWindow, err := time.ParseDuration(windowStr)
+	if err != nil || overrideWindow <= 0 {
+		log.Printf("Ignoring invalid %s=%q, expected <limit>/<window>", envName, override)
+		return policy
+	}
+
+	policy.Limit = overrideLimit
+	policy.Window = overrideWindow
+	return policy
+}
+
+func RateLimitByIP(ctx *gin.Context) string {
+	return "ip:" + ctx.ClientIP()
+}
+
+func RateLimitByUser(ctx *gin.Context) string {
+	userContext, exists := ctx.Get("user")
+	if !exists {
+		return RateLimitByIP(ctx)
+	}
+	return "user:" + userContext.(*UserClaims).UserId
+}
+
+func RateLimitByEmail(ctx *gin.Context) string {
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		return RateLimitByIP(ctx)
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var request struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil || request.Email == "" {
+		return RateLimitByIP(ctx)
+	}
+	return "email:" + strings.ToLower(strings.TrimSpace(request.Email))
+}
+
+func RateLimit(policy carwise.RateLimitPolicy, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		result, errorMessages := interactor.AllowRequest(policy, keyFunc(ctx))
+		if errorMessages != nil {
+			log.Println("Rate limit check failed:", errorMessages)
+			ctx.Next()
+			return
+		}
+
+		reset := int(math.Ceil(result.Reset.Seconds()))
+		header := ctx.Writer.Header()
+		current, err := strconv.Atoi(header.Get("RateLimit-Remaining"))
+		if err != nil || result.Remaining <= current {
+			ctx.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Window.Seconds())))
+			ctx.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+			ctx.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			ctx.Header("RateLimit-Reset", strconv.Itoa(reset))
+		}
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", strconv.Itoa(reset))
+			ctx.JSON(http.StatusTooManyRequests, gin.H{"error": []string{"Too many requests. Please try again later."}})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"carwise"
+	"infra"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewRateLimitPolicy(t *testing.T) {
+	tests := []struct {
+		name       string
+		override   string
+		wantLimit  int
+		wantWindow time.Duration
+	}{
+		{name: "default", override: "", wantLimit: 5, wantWindow: time.Minute},
+		{name: "override", override: "10/1h", wantLimit: 10, wantWindow: time.Hour},
+		{name: "missing window", override: "10", wantLimit: 5, wantWindow: time.Minute},
+		{name: "invalid limit", override: "zero/1h", wantLimit: 5, wantWindow: time.Minute},
+		{name: "negative limit", override: "-1/1h", wantLimit: 5, wantWindow: time.Minute},
+		{name: "invalid window", override: "10/soon", wantLimit: 5, wantWindow: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATE_LIMIT_PASSWORD_RESET", tt.override)
+			policy := NewRateLimitPolicy("password-reset", 5, time.Minute)
+			if policy.Name != "password-reset" || policy.Limit != tt.wantLimit || policy.Window != tt.wantWindow {
+				t.Errorf("got %+v, want limit %d window %v", policy, tt.wantLimit, tt.wantWindow)
+			}
+		})
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	interactor = carwise.NewInteractor(carwise.Services{
+		RateLimitRepo: infra.NewMemoryRateLimitRepository(),
+	}, carwise.Config{})
+
+	router := gin.New()
+	router.GET("/limited", RateLimit(carwise.RateLimitPolicy{Name: "test", Limit: 2, Window: time.Minute}, RateLimitByIP), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	request := func(ip string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = ip + ":1234"
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	for n, wantRemaining := range []string{"1", "0"} {
+		recorder := request("10.0.0.1")
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d", n, recorder.Code)
+		}
+		if got := recorder.Header().Get("RateLimit-Remaining"); got != wantRemaining {
+			t.Errorf("request %d: got RateLimit-Remaining %q, want %q", n, got, wantRemaining)
+		}
+		if got := recorder.Header().Get("RateLimit-Policy"); got != "2;w=60" {
+			t.Errorf("request %d: got RateLimit-Policy %q", n, got)
+		}
+	}
+
+	recorder := request("10.0.0.1")
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d", recorder.Code, http.StatusTooManyRequests)
+	}
+	if recorder.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	if recorder := request("10.0.0.2"); recorder.Code != http.StatusOK {
+		t.Errorf("expected another IP to be allowed, got status %d", recorder.Code)
+	}
+}
@@ -31,7 +31,15 @@ func init() {
 	validate.RegisterValidation("body_type", validateBodyType)
 	validate.RegisterValidation("condition", validateCondition)
 	validate.RegisterValidation("drive_type", validateDriveType)
-	validate.RegisterValidation("seller_type", validateSellerType)
+	validate.RegisterValidation("account_status", validateAccountStatus)
+	validate.RegisterValidation("user_role", validateUserRole)
+	validate.RegisterValidation("listing_action", validateListingAction)
+	validate.RegisterValidation("organization_role", validateOrganizationRole)
+	validate.RegisterValidation("alert_frequency", validateAlertFrequency)
+	validate.RegisterValidation("moderation_status", validateModerationStatus)
+	validate.RegisterValidation("offer_action", validateOfferAction)
+	validate.RegisterValidation("notification_type", validateNotificationType)
+	validate.RegisterValidation("locale", validateLocale)
 }
 
 func strongPassword(fl validator.FieldLevel) bool {
@@ -89,12 +97,63 @@ func validateDriveType(fl validator.FieldLevel) bool {
 	return driveType == carwise.DriveTypeFrontWheelDrive || driveType == carwise.DriveTypeRearWheelDrive || driveType == carwise.DriveTypeFourWheelDrive || driveType == carwise.DriveTypeAllWheelDrive
 }
 
-func validateSellerType(fl validator.FieldLevel) bool {
-	sellerType := fl.Field().String()
-	return sellerType == carwise.SellerTypeIndividual || sellerType == carwise.SellerTypeDealer
-}
-
 func validateTransmission(fl validator.FieldLevel) bool {
 	transmissionType := fl.Field().String()
 	return transmissionType == carwise.TransmissionAutomatic || transmissionType == carwise.TransmissionManual || transmissionType == carwise.TransmissionSemiautomatic
 }
+
+func validateAccountStatus(fl validator.FieldLevel) bool {
+	status := fl.Field().String()
+	return status == carwise.AccountStatusActive || status == carwise.AccountStatusInactive || status == carwise.AccountStatusBanned
+}
+
+func validateUserRole(fl validator.FieldLevel) bool {
+	role := fl.Field().String()
+	_, ok := carwise.RolePermissions[role]
+	return ok
+}
+
+func validateListingAction(fl validator.FieldLevel) bool {
+	action := fl.Field().String()
+	return action == carwise.ListingActionPause || action == carwise.ListingActionRenew || action == carwise.ListingActionMarkSold
+}
+
+func validateOrganizationRole(fl validator.FieldLevel) bool {
+	role := fl.Field().String()
+	return role == carwise.OrganizationRoleOwner || role == carwise.OrganizationRoleManager || role == carwise.OrganizationRoleSalesperson
+}
+
+func validateAlertFrequency(fl validator.FieldLevel) bool {
+	frequency := fl.Field().String()
+	return frequency == carwise.AlertFrequencyInstant || frequency == carwise.AlertFrequencyDaily || frequency == carwise.AlertFrequencyWeekly || frequency == carwise.AlertFrequencyNone
+}
+
+func validateModerationStatus(fl validator.FieldLevel) bool {
+	status := fl.Field().String()
+	return status == carwise.CarStatusActive || status == carwise.CarStatusRemoved
+}
+
+func validateOfferAction(fl validator.FieldLevel) bool {
+	action := fl.Field().String()
+	return action == carwise.OfferActionAccept || action == carwise.OfferActionReject || action == carwise.OfferActionCounter
+}
+
+func validateNotificationType(fl validator.FieldLevel) bool {
+	notificationType := fl.Field().String()
+	for _, t := range carwise.NotificationTypes {
+		if notificationType == t {
+			return true
+		}
+	}
+	return false
+}
+
+func validateLocale(fl validator.FieldLevel) bool {
+	locale := fl.Field().String()
+	for _, l := range carwise.Locales {
+		if locale == l {
+			return true
+		}
+	}
+	return false
+}
@@ -1,21 +1,56 @@
 package carwise
 
 import (
+	"errors"
 	"io"
 	"time"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
+var ErrEmailTemplateNotFound = errors.New("email template not found")
+
+var ErrOfferConflict = errors.New("offer was changed concurrently")
+
+var ErrCarNotAvailable = errors.New("car is no longer available")
+
+var ErrLastOrganizationOwner = errors.New("organization must keep an owner")
+
+var ErrInvitationNotFound = errors.New("invitation not found")
+
+var ErrImportRunning = errors.New("another import is still running")
+
 type UserRepository interface {
 	Create(*User) error
 	GetByID(id string) (*User, error)
 	GetByEmail(email string) (*User, error)
 	UpdatePassword(email, hashedPassword string) error
+	UpdateEmail(id, email string) error
 	Update(user *User) error
+	UpdateStatus(id, status string) error
+	UpdateRole(id, role string) error
+	Search(query, role, status string, page, limit int) ([]User, error)
+	AddStatusChange(change *UserStatusChange) error
+	GetStatusChanges(userID string) ([]UserStatusChange, error)
+	ScheduleDeletion(id string, at time.Time) error
+	CancelDeletion(id string) error
+	GetDeletionsDue(before time.Time) ([]User, error)
+	Anonymize(user *User) error
+	UpdateLastLogin(id string, at time.Time) error
+}
+
+type LoginEventRepository interface {
+	Create(event *LoginEvent) error
+	Search(query, userID, outcome string, page, limit int) ([]LoginEvent, error)
 }
 
 type TokenRepository interface {
 	IsTokenBlackListed(token string) (bool, error)
 	AddTokenBlackList(token string) error
+	RevokeUserTokens(userID string, at time.Time) error
+	GetUserTokensRevokedAt(userID string) (time.Time, error)
+	SaveEventTicket(ticketHash string, session *EventSession, ttl time.Duration) error
+	ConsumeEventTicket(ticketHash string) (*EventSession, error)
 }
 
 type AuxiliaryRepository interface {
@@ -25,31 +60,287 @@ type AuxiliaryRepository interface {
 }
 
 type MailGateway interface {
-	Send(To string, Body []byte) error
+	Send(email *Email) error
+}
+
+type EmailRenderer interface {
+	Render(name, locale string, data map[string]interface{}) (*Email, error)
+	Translate(locale, key string, args ...interface{}) string
+	Templates() []string
+}
+
+type SMSGateway interface {
+	Send(To string, Body string) error
+}
+
+type PhoneRelayGateway interface {
+	Allocate(phoneNumber, carID, viewerID string, ttl time.Duration) (string, string, error)
+}
+
+type LoginAttemptRepository interface {
+	RecordFailure(key string, window time.Duration) (int64, error)
+	ResetFailures(key string) error
+	Lock(key string, ttl time.Duration) error
+	LockedFor(key string) (time.Duration, error)
+}
+
+type RateLimitRepository interface {
+	Allow(key string, limit int, window time.Duration) (*RateLimitResult, error)
 }
 
 type PasswordResetRepository interface {
-	SaveResetCode(email, code string, ttl time.Duration) error
-	VerifyResetCode(email, code string) (bool, error)
+	SaveResetCode(email, codeHash string, ttl time.Duration) error
+	ConsumeResetCode(email, codeHash string) (bool, error)
+	IncrementAttempts(email string, window time.Duration) (int64, error)
 	DeleteResetCode(email string) error
 }
 
+type EmailVerificationRepository interface {
+	SaveVerificationCode(email, codeHash string, ttl time.Duration) error
+	VerifyVerificationCode(email, codeHash string) (bool, error)
+	DeleteVerificationCode(email string) error
+	IncrementResendCount(email string, window time.Duration) (int64, error)
+}
+
+type EmailChangeRepository interface {
+	SaveEmailChange(userID string, change *EmailChange, ttl time.Duration) error
+	GetEmailChange(userID string) (*EmailChange, error)
+	DeleteEmailChange(userID string) error
+}
+
+type AccountDeletionRepository interface {
+	SaveDeletionCode(userID, codeHash string, ttl time.Duration) error
+	VerifyDeletionCode(userID, codeHash string) (bool, error)
+	IncrementAttempts(userID string, window time.Duration) (int64, error)
+	DeleteDeletionCode(userID string) error
+}
+
+type IdentityProvider interface {
+	AuthCodeURL(state, codeVerifier, nonce string) string
+	Exchange(code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
+
+type UserIdentityRepository interface {
+	Create(identity *UserIdentity) error
+	GetByProviderSubject(provider, subject string) (*UserIdentity, error)
+	GetByUser(userID string) ([]UserIdentity, error)
+}
+
+type OAuthStateRepository interface {
+	SaveState(state string, data *OAuthState, ttl time.Duration) error
+	ConsumeState(state string) (*OAuthState, error)
+}
+
+type PhoneVerificationRepository interface {
+	SaveOTP(userID string, otp *PhoneOTP, ttl time.Duration) error
+	GetOTP(userID string) (*PhoneOTP, error)
+	IncrementOTPAttempts(userID string) (int64, error)
+	DeleteOTP(userID string) error
+	IncrementSendCount(userID string, window time.Duration) (int64, error)
+}
+
+type RecoveryCodeRepository interface {
+	ReplaceRecoveryCodes(userID string, codeHashes []string) error
+	UseRecoveryCode(userID, codeHash string) (bool, error)
+	DeleteRecoveryCodes(userID string) error
+}
+
+type TwoFactorChallengeRepository interface {
+	SaveChallenge(token, userID string, ttl time.Duration) error
+	GetChallenge(token string) (string, error)
+	IncrementChallengeAttempts(token string) (int64, error)
+	DeleteChallenge(token string) error
+	MarkCodeUsed(userID string, step int64, ttl time.Duration) (bool, error)
+}
+
+type OrganizationRepository interface {
+	Create(organization *Organization, owner *OrganizationMember) error
+	GetByID(id string) (*Organization, error)
+	Update(organization *Organization) error
+	SetVerified(id string, verified bool, at time.Time) error
+	Search(query, verification string, page, limit int) ([]Organization, error)
+	CountActiveListings(id string) (int, error)
+	GetMember(organizationID, userID string) (*OrganizationMember, error)
+	GetMembers(organizationID string) ([]OrganizationMember, error)
+	GetMemberships(userID string) ([]OrganizationMember, error)
+	UpdateMemberRole(organizationID, userID, role string) error
+	RemoveMember(organizationID, userID string) error
+	SaveInvitation(invitation *OrganizationInvitation) error
+	GetInvitation(id string) (*OrganizationInvitation, error)
+	GetInvitations(organizationID string, now time.Time) ([]OrganizationInvitation, error)
+	GetInvitationsByEmail(email string, now time.Time) ([]OrganizationInvitation, error)
+	AcceptInvitation(id, userID string, at time.Time) error
+	DeleteInvitation(id string) error
+}
+
+type SavedSearchRepository interface {
+	Create(search *SavedSearch) error
+	Update(search *SavedSearch) error
+	Delete(id string) error
+	GetByID(id string) (*SavedSearch, error)
+	GetByUser(userID string) ([]SavedSearch, error)
+	GetByUnsubscribeToken(token string) (*SavedSearch, error)
+	ClaimDue(now time.Time) ([]SavedSearch, error)
+	MarkRun(id string, at time.Time) error
+}
+
+type PriceHistoryRepository interface {
+	Create(change *PriceChange) error
+	GetByCar(carID string) ([]PriceChange, error)
+}
+
+type FavoriteRepository interface {
+	Add(favorite *Favorite) error
+	Remove(userID, carID string) error
+	GetByUser(userID string, page, limit int) ([]Favorite, error)
+	GetFavoriteCarIDs(userID string, carIDs []string) (map[string]bool, error)
+	GetUserIDsByCar(carID string) ([]string, error)
+	CountByCars(carIDs []string) (map[string]int, error)
+}
+
+type ConversationRepository interface {
+	Create(conversation *Conversation) error
+	GetByID(id string) (*Conversation, error)
+	GetByCarAndBuyer(carID, buyerID string) (*Conversation, error)
+	GetByUser(userID string, page, limit int) ([]Conversation, error)
+	GetIDsByUser(userID string) ([]string, error)
+	CreateMessage(message *Message) error
+	GetMessages(conversationID string, page, limit int) ([]Message, error)
+	GetLastMessages(conversationIDs []string) (map[string]Message, error)
+	MarkRead(conversationID, userID string, at time.Time) error
+	CountUnread(userID string, conversationIDs []string) (map[string]int, error)
+	CountAllUnread(userID string) (int, error)
+	ClaimUnnotified(before, at time.Time) ([]Message, error)
+	ReleaseNotified(ids []string) error
+	CountByCars(carIDs []string) (map[string]int, error)
+	GetResponseStats(sellerID string) (*ResponseStats, error)
+}
+
+type UserBlockRepository interface {
+	Block(block *UserBlock) error
+	Unblock(blockerID, blockedID string) error
+	IsBlocked(userID, otherID string) (bool, error)
+	GetBlockedIDs(blockerID string) (map[string]bool, error)
+}
+
+type OfferRepository interface {
+	Create(offer *Offer) error
+	Update(offer *Offer, expectedStatus string) error
+	Accept(offer *Offer, expectedStatus string) ([]Offer, error)
+	GetByID(id string) (*Offer, error)
+	GetOpenByCarAndBuyer(carID, buyerID string) (*Offer, error)
+	GetByUser(userID, role, status string, page, limit int) ([]Offer, error)
+	GetExpired(before time.Time) ([]Offer, error)
+}
+
+type NotificationRepository interface {
+	Create(notification *Notification) error
+	GetByUser(userID string, unreadOnly bool, page, limit int) ([]Notification, error)
+	CountUnread(userID string) (int, error)
+	MarkRead(userID, id string, at time.Time) (bool, error)
+	MarkAllRead(userID string, at time.Time) error
+	GetPreference(userID, notificationType string) (*NotificationPreference, error)
+	GetPreferences(userID string) ([]NotificationPreference, error)
+	SavePreferences(preferences []NotificationPreference) error
+}
+
+type EventBroker interface {
+	Publish(event *Event) error
+	Subscribe(userID string) (<-chan Event, func())
+}
+
+type ImportJobRepository interface {
+	Create(job *ImportJob) error
+	Update(job *ImportJob) error
+	GetByID(id string) (*ImportJob, error)
+	GetByOrganization(organizationID string, page, limit int) ([]ImportJob, error)
+	FailStale(before time.Time, reason string) (int64, error)
+	AddRows(rows []ImportJobRow) error
+	GetRows(jobID string) ([]ImportJobRow, error)
+}
+
+type ListingFeedParser interface {
+	Parse(feed io.Reader) ([]map[string]string, error)
+}
+
 type CDNRepository interface {
 	SaveUserAvatar(userID string, image io.Reader) (string, error)
+	SaveOrganizationLogo(organizationID string, image io.Reader) (string, error)
+	OpenUserAvatar(userID string) (io.ReadCloser, error)
+	DeleteUserAvatar(userID string) error
+	SaveMessageAttachment(conversationID, messageID string, index int, image io.Reader) (string, error)
+	OpenMessageAttachment(conversationID, fileName string) (io.ReadCloser, error)
+	DeleteMessageAttachment(conversationID, fileName string) error
+	DeleteConversationAttachments(conversationID string) error
 }
 
 type CarRepository interface {
 	Create(car *Car) error
 	GetCars(page, limit, brand_id, series_id, model_id int) ([]Car, error)
+	GetListedBetween(brandID, seriesID, modelID int, since, until time.Time, limit int) ([]Car, error)
 	GetByID(id string) (*Car, error)
+	GetByIDs(ids []string) ([]Car, error)
+	GetByOwner(ownerID string) ([]Car, error)
+	GetManagedBy(userID string) ([]Car, error)
+	GetByStockNumber(organizationID, stockNumber string) (*Car, error)
+	UpdateListing(car *Car) error
+	SetStatus(id, status string) error
+	SetOrganizationSellerType(organizationID, sellerType string) error
+	ExpireMissingStock(organizationID string, stockNumbers []string) (int64, error)
+	GetActiveByOwner(ownerID string, page, limit int) ([]Car, error)
+	GetActiveByOrganization(organizationID string, page, limit int) ([]Car, error)
+	GetSellerStats(ownerID string) (*SellerStats, error)
+	IncrementViewCount(id string) error
+	UpdateStatus(userID string, ids []string, fromStatuses []string, status string, expiresAt time.Time) ([]string, error)
+	ExpireListings(before time.Time) (int64, error)
+	GetExpiring(before time.Time) ([]Car, error)
+	MarkExpiryNotified(id string, at time.Time) error
+}
+
+type ContactRevealRepository interface {
+	Create(reveal *ContactReveal) error
+	CountByCars(carIDs []string) (map[string]int, error)
 }
 
 type Services struct {
-	UserRepo          UserRepository
-	TokenRepo         TokenRepository
-	AuxRepo           AuxiliaryRepository
-	MailGW            MailGateway
-	PasswordResetRepo PasswordResetRepository
-	CDNRepo           CDNRepository
-	CarRepo           CarRepository
+	UserRepo              UserRepository
+	TokenRepo             TokenRepository
+	AuxRepo               AuxiliaryRepository
+	MailGW                MailGateway
+	EmailRenderer         EmailRenderer
+	SMSGW                 SMSGateway
+	PhoneRelayGW          PhoneRelayGateway
+	PasswordResetRepo     PasswordResetRepository
+	EmailVerificationRepo EmailVerificationRepository
+	EmailChangeRepo       EmailChangeRepository
+	AccountDeletionRepo   AccountDeletionRepository
+	IdentityProviders     map[string]IdentityProvider
+	UserIdentityRepo      UserIdentityRepository
+	OAuthStateRepo        OAuthStateRepository
+	PhoneVerificationRepo PhoneVerificationRepository
+	RecoveryCodeRepo      RecoveryCodeRepository
+	TwoFactorRepo         TwoFactorChallengeRepository
+	LoginAttemptRepo      LoginAttemptRepository
+	LoginEventRepo        LoginEventRepository
+	RateLimitRepo         RateLimitRepository
+	CDNRepo               CDNRepository
+	CarRepo               CarRepository
+	ContactRevealRepo     ContactRevealRepository
+	OrganizationRepo      OrganizationRepository
+	ImportJobRepo         ImportJobRepository
+	FavoriteRepo          FavoriteRepository
+	SavedSearchRepo       SavedSearchRepository
+	PriceHistoryRepo      PriceHistoryRepository
+	ConversationRepo      ConversationRepository
+	UserBlockRepo         UserBlockRepository
+	OfferRepo             OfferRepository
+	NotificationRepo      NotificationRepository
+	EventBroker           EventBroker
+	FeedParsers           map[string]ListingFeedParser
+	ValidateRequest       func(request interface{}) []string
+}
+
+type Config struct {
+	FrontendURL string
+	APIURL      string
 }
@@ -9,6 +9,7 @@ type UserCreateRequest struct {
 	PhoneNumber string `json:"phone_number" validate:"required"`
 	Email       string `json:"email" validate:"required,email"`
 	Password    string `json:"password" validate:"required,strong_password"`
+	Locale      string `json:"locale" validate:"omitempty,locale"`
 }
 
 type UserLoginRequest struct {
@@ -45,17 +46,140 @@ type ChangePasswordRequest struct {
 	RePassword string `json:"re_password" validate:"required,strong_password,password_match"`
 }
 
+type VerifyEmailRequest struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+	Token string `json:"token" form:"token" validate:"required"`
+}
+
+type UpdatePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	Password        string `json:"password" validate:"required,strong_password"`
+	RePassword      string `json:"re_password" validate:"required,strong_password,password_match"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	UserID string `json:"user_id" form:"user_id" validate:"required"`
+	Token  string `json:"token" form:"token" validate:"required"`
+}
+
+type SocialLoginRequest struct {
+	Code  string `json:"code" form:"code" validate:"required"`
+	State string `json:"state" form:"state" validate:"required"`
+}
+
 type ProfileResponse struct {
-	ID          string    `json:"id"`
-	FirstName   string    `json:"first_name"`
-	LastName    string    `json:"last_name"`
-	ImageUrl    string    `json:"image_url"`
-	CountryCode string    `json:"country_code"`
-	PhoneNumber string    `json:"phone_number"`
-	Email       string    `json:"email"`
-	Role        string    `json:"role"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID                  string     `json:"id"`
+	FirstName           string     `json:"first_name"`
+	LastName            string     `json:"last_name"`
+	ImageUrl            string     `json:"image_url"`
+	CountryCode         string     `json:"country_code"`
+	PhoneNumber         string     `json:"phone_number"`
+	Email               string     `json:"email"`
+	Role                string     `json:"role"`
+	Status              string     `json:"status"`
+	EmailVerified       bool       `json:"email_verified"`
+	PhoneVerified       bool       `json:"phone_verified"`
+	TwoFactorEnabled    bool       `json:"two_factor_enabled"`
+	ShowPhoneNumber     bool       `json:"show_phone_number"`
+	Locale              string     `json:"locale"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+type PrivacySettingsRequest struct {
+	ShowPhoneNumber *bool `json:"show_phone_number" validate:"required"`
+}
+
+type PublicProfileResponse struct {
+	ID             string    `json:"id"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	ImageUrl       string    `json:"image_url,omitempty"`
+	SellerType     string    `json:"seller_type"`
+	ActiveListings int       `json:"active_listings"`
+	SoldListings   int       `json:"sold_listings"`
+	ResponseRate   *float64  `json:"response_rate"`
+	PhoneVerified  bool      `json:"phone_verified"`
+	MemberSince    time.Time `json:"member_since"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required_without=Code"`
+	Code     string `json:"code" validate:"required_without=Password"`
+}
+
+type LinkedAccountResponse struct {
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccountExportResponse struct {
+	ExportedAt     time.Time                    `json:"exported_at"`
+	Profile        ProfileResponse              `json:"profile"`
+	Listings       []CarDetailResponse          `json:"listings"`
+	Favorites      []FavoriteResponse           `json:"favorites"`
+	SavedSearches  []SavedSearchResponse        `json:"saved_searches"`
+	Conversations  []ConversationExportResponse `json:"conversations"`
+	Offers         []OfferResponse              `json:"offers"`
+	Notifications  []NotificationResponse       `json:"notifications"`
+	LinkedAccounts []LinkedAccountResponse      `json:"linked_accounts"`
+	StatusHistory  []UserStatusChangeResponse   `json:"status_history"`
+	SecurityEvents []SecurityEventResponse      `json:"security_events"`
+}
+
+type ConversationExportResponse struct {
+	ConversationResponse
+	Messages []MessageResponse `json:"messages"`
+}
+
+type SecurityEventResponse struct {
+	Method    string    `json:"method"`
+	Outcome   string    `json:"outcome"`
+	Reason    string    `json:"reason,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AdminLoginEventResponse struct {
+	ID     string `json:"id"`
+	UserID string `json:"user_id,omitempty"`
+	Email  string `json:"email"`
+	SecurityEventResponse
+}
+
+type TwoFactorSetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCode     string `json:"qr_code,omitempty"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type PhoneVerifyRequest struct {
+	Code string `json:"code" validate:"required,numeric,len=6"`
 }
 
 type ProfileEditRequest struct {
@@ -63,6 +187,7 @@ type ProfileEditRequest struct {
 	LastName    string `json:"last_name" validate:"required,min=2,max=50"`
 	CountryCode string `json:"country_code" validate:"required,max=10"`
 	PhoneNumber string `json:"phone_number" validate:"required"`
+	Locale      string `json:"locale" validate:"omitempty,locale"`
 }
 
 type CarCreateRequest struct {
@@ -89,9 +214,9 @@ type CarCreateRequest struct {
 	EngineVolume      int       `json:"engine_volume" validate:"required"`
 	DriveType         string    `json:"drive_type" validate:"required,drive_type"`
 	Color             string    `json:"color" validate:"required"`
-	Warranty          bool      `json:"warranty" validate:"required"`
+	Warranty          bool      `json:"warranty"`
 	HeavyDamage       bool      `json:"heavy_damage"`
-	SellerType        string    `json:"seller_type" validate:"required,seller_type"`
+	OrganizationID    string    `json:"organization_id"`
 	TradeOption       bool      `json:"trade_option"`
 	FrontBumper       string    `json:"front_bumper" validate:"required,condition"`
 	FrontHood         string    `json:"front_hood" validate:"required,condition"`
@@ -133,7 +258,7 @@ func (r CarCreateRequest) ToCar() *Car {
 		Color:             r.Color,
 		Warranty:          r.Warranty,
 		HeavyDamage:       r.HeavyDamage,
-		SellerType:        r.SellerType,
+		SellerType:        SellerTypeIndividual,
 		TradeOption:       r.TradeOption,
 		FrontBumper:       r.FrontBumper,
 		FrontHood:         r.FrontHood,
@@ -145,6 +270,8 @@ func (r CarCreateRequest) ToCar() *Car {
 		RearLeftDoor:      r.RearLeftDoor,
 		RearLeftMudguard:  r.RearLeftMudguard,
 		RearBumper:        r.RearBumper,
+		OrganizationID:    r.OrganizationID,
+		Status:            CarStatusActive,
 	}
 }
 
@@ -162,54 +289,389 @@ type ListCarResponse struct {
 	ListingDate time.Time `json:"listing_date,omitempty"`
 	City        string    `json:"city,omitempty"`
 	District    string    `json:"district,omitempty"`
+	IsFavorite  *bool     `json:"is_favorite,omitempty"`
+}
+
+type MyListingResponse struct {
+	ListCarResponse
+	Status             string    `json:"status"`
+	ExpiresAt          time.Time `json:"expires_at"`
+	ViewCount          int       `json:"view_count"`
+	ContactRevealCount int       `json:"contact_reveal_count"`
+	FavoriteCount      int       `json:"favorite_count"`
+	MessageCount       int       `json:"message_count"`
+}
+
+type SavedSearchRequest struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	BrandID   int    `json:"brand_id" validate:"omitempty,min=1"`
+	SeriesID  int    `json:"series_id" validate:"omitempty,min=1"`
+	ModelID   int    `json:"model_id" validate:"omitempty,min=1"`
+	Frequency string `json:"frequency" validate:"required,alert_frequency"`
+}
+
+type SavedSearchResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	BrandID   int       `json:"brand_id,omitempty"`
+	SeriesID  int       `json:"series_id,omitempty"`
+	ModelID   int       `json:"model_id,omitempty"`
+	Frequency string    `json:"frequency"`
+	LastRunAt time.Time `json:"last_run_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type FavoriteResponse struct {
+	ListCarResponse
+	Status      string    `json:"status"`
+	FavoritedAt time.Time `json:"favorited_at"`
+}
+
+type ConversationCreateRequest struct {
+	CarID string `json:"car_id" validate:"required"`
+	Body  string `json:"body" validate:"max=2000"`
+}
+
+type MessageRequest struct {
+	Body string `json:"body" validate:"max=2000"`
+}
+
+type ConversationResponse struct {
+	ID            string           `json:"id"`
+	CarID         string           `json:"car_id"`
+	CarTitle      string           `json:"car_title"`
+	Role          string           `json:"role"`
+	Counterpart   OwnerResponse    `json:"counterpart"`
+	LastMessage   *MessageResponse `json:"last_message,omitempty"`
+	UnreadCount   int              `json:"unread_count"`
+	Blocked       bool             `json:"blocked"`
+	LastMessageAt time.Time        `json:"last_message_at"`
+	CreatedAt     time.Time        `json:"created_at"`
+}
+
+type MessageResponse struct {
+	ID             string     `json:"id"`
+	ConversationID string     `json:"conversation_id"`
+	SenderID       string     `json:"sender_id"`
+	Body           string     `json:"body,omitempty"`
+	Attachments    []string   `json:"attachments,omitempty"`
+	ReadAt         *time.Time `json:"read_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type OfferRequest struct {
+	Amount       float64 `json:"amount" validate:"gte=0"`
+	TradeInCarID string  `json:"trade_in_car_id"`
+	Message      string  `json:"message" validate:"max=1000"`
+}
+
+type OfferActionRequest struct {
+	Action  string  `json:"action" validate:"required,offer_action"`
+	Amount  float64 `json:"amount" validate:"required_if=Action counter,gte=0"`
+	Message string  `json:"message" validate:"max=1000"`
+}
+
+type OfferResponse struct {
+	ID        string           `json:"id"`
+	CarID     string           `json:"car_id"`
+	CarTitle  string           `json:"car_title"`
+	Role      string           `json:"role"`
+	Buyer     OwnerResponse    `json:"buyer"`
+	SellerID  string           `json:"seller_id"`
+	Amount    float64          `json:"amount"`
+	Currency  string           `json:"currency"`
+	TradeIn   *ListCarResponse `json:"trade_in,omitempty"`
+	Message   string           `json:"message,omitempty"`
+	Status    string           `json:"status"`
+	ExpiresAt time.Time        `json:"expires_at"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+type NotificationResponse struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Body      string      `json:"body"`
+	Link      string      `json:"link,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Read      bool        `json:"read"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type EventTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type NotificationListResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Unread        int                    `json:"unread"`
+}
+
+type NotificationPreferenceRequest struct {
+	Type  string `json:"type" validate:"required,notification_type"`
+	InApp bool   `json:"in_app"`
+	Email bool   `json:"email"`
+}
+
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceRequest `json:"preferences" validate:"required,min=1,dive"`
+}
+
+type NotificationPreferenceResponse struct {
+	Type  string `json:"type"`
+	InApp bool   `json:"in_app"`
+	Email bool   `json:"email"`
+}
+
+type ListingExpiringEventResponse struct {
+	CarID     string    `json:"car_id"`
+	Title     string    `json:"title"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type OfferEventResponse struct {
+	OfferID  string  `json:"offer_id"`
+	CarID    string  `json:"car_id"`
+	Title    string  `json:"title"`
+	Status   string  `json:"status"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+type PriceDropEventResponse struct {
+	CarID    string  `json:"car_id"`
+	Title    string  `json:"title"`
+	OldPrice float64 `json:"old_price"`
+	NewPrice float64 `json:"new_price"`
+	Currency string  `json:"currency"`
+}
+
+type ListingModerationRequest struct {
+	Status string `json:"status" validate:"required,moderation_status"`
+	Reason string `json:"reason" validate:"required,min=5,max=500"`
+}
+
+type EmailPreviewResponse struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	Text     string `json:"text"`
+	HTML     string `json:"html"`
+}
+
+type ListingModerationEventResponse struct {
+	CarID  string `json:"car_id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type UnreadCountResponse struct {
+	Unread int `json:"unread"`
+}
+
+type BulkListingActionRequest struct {
+	Action string   `json:"action" validate:"required,listing_action"`
+	CarIDs []string `json:"car_ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+type ImportJobResponse struct {
+	ID           string                 `json:"id"`
+	Format       string                 `json:"format"`
+	FullSync     bool                   `json:"full_sync"`
+	Status       string                 `json:"status"`
+	TotalRows    int                    `json:"total_rows"`
+	CreatedCount int                    `json:"created_count"`
+	UpdatedCount int                    `json:"updated_count"`
+	RemovedCount int                    `json:"removed_count"`
+	FailedCount  int                    `json:"failed_count"`
+	Error        string                 `json:"error,omitempty"`
+	Rows         []ImportJobRowResponse `json:"rows,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+	FinishedAt   *time.Time             `json:"finished_at,omitempty"`
+}
+
+type ImportJobRowResponse struct {
+	Row         int      `json:"row"`
+	StockNumber string   `json:"stock_number"`
+	Result      string   `json:"result"`
+	CarID       string   `json:"car_id,omitempty"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+type BulkListingActionResponse struct {
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+type OrganizationRequest struct {
+	Name        string `json:"name" validate:"required,min=2,max=255"`
+	TaxNumber   string `json:"tax_number" validate:"required,numeric,min=10,max=11"`
+	TaxOffice   string `json:"tax_office" validate:"required,max=100"`
+	Address     string `json:"address" validate:"required,max=500"`
+	City        string `json:"city" validate:"required,max=100"`
+	CountryCode string `json:"country_code" validate:"required,max=10"`
+	PhoneNumber string `json:"phone_number" validate:"required"`
+}
+
+type OrganizationMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,organization_role"`
+}
+
+type OrganizationMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,organization_role"`
+}
+
+type OrganizationVerifyRequest struct {
+	Verified *bool `json:"verified" validate:"required"`
+}
+
+type OrganizationSummaryResponse struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	LogoUrl  string `json:"logo_url,omitempty"`
+	City     string `json:"city"`
+	Verified bool   `json:"verified"`
+}
+
+type OrganizationResponse struct {
+	OrganizationSummaryResponse
+	TaxNumber      string     `json:"tax_number,omitempty"`
+	TaxOffice      string     `json:"tax_office,omitempty"`
+	Address        string     `json:"address"`
+	CountryCode    string     `json:"country_code,omitempty"`
+	PhoneNumber    string     `json:"phone_number,omitempty"`
+	ActiveListings int        `json:"active_listings"`
+	VerifiedAt     *time.Time `json:"verified_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type OrganizationMemberResponse struct {
+	UserID    string    `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationInvitationResponse struct {
+	ID           string                       `json:"id"`
+	Organization *OrganizationSummaryResponse `json:"organization,omitempty"`
+	Email        string                       `json:"email"`
+	Role         string                       `json:"role"`
+	CreatedAt    time.Time                    `json:"created_at"`
+	ExpiresAt    time.Time                    `json:"expires_at"`
+}
+
+type MembershipResponse struct {
+	Organization OrganizationResponse `json:"organization"`
+	Role         string               `json:"role"`
+}
+
+type PricePointResponse struct {
+	Price     float64   `json:"price"`
+	Currency  string    `json:"currency"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
 type CarDetailResponse struct {
-	ID                string        `json:"id,omitempty"`
-	Owner             OwnerResponse `json:"owner,omitempty"`
-	Title             string        `json:"title,omitempty"`
-	Description       string        `json:"description,omitempty"`
-	Currency          string        `json:"currency,omitempty"`
-	Price             float64       `json:"price,omitempty"`
-	City              string        `json:"city,omitempty"`
-	District          string        `json:"district,omitempty"`
-	Neighborhood      string        `json:"neighborhood,omitempty"`
-	ListingNumber     string        `json:"listing_number,omitempty"`
-	ListingDate       time.Time     `json:"listing_date,omitempty"`
-	Brand             string        `json:"brand,omitempty"`
-	Series            string        `json:"series,omitempty"`
-	Model             string        `json:"model,omitempty"`
-	Year              int           `json:"year,omitempty"`
-	FuelType          string        `json:"fuel_type,omitempty"`
-	Transmission      string        `json:"transmission,omitempty"`
-	Mileage           int           `json:"mileage,omitempty"`
-	BodyType          string        `json:"body_type,omitempty"`
-	EnginePower       int           `json:"engine_power,omitempty"`
-	EngineVolume      int           `json:"engine_volume,omitempty"`
-	DriveType         string        `json:"drive_type,omitempty"`
-	Color             string        `json:"color,omitempty"`
-	Warranty          bool          `json:"warranty,omitempty"`
-	HeavyDamage       bool          `json:"heavy_damage,omitempty"`
-	SellerType        string        `json:"seller_type,omitempty"`
-	TradeOption       bool          `json:"trade_option,omitempty"`
-	FrontBumper       string        `json:"front_bumper,omitempty"`
-	FrontHood         string        `json:"front_hood,omitempty"`
-	Roof              string        `json:"roof,omitempty"`
-	FrontRightDoor    string        `json:"front_right_door,omitempty"`
-	RearRightDoor     string        `json:"rear_right_door,omitempty"`
-	FrontLeftMudguard string        `json:"front_left_mudguard,omitempty"`
-	FrontLeftDoor     string        `json:"front_left_door,omitempty"`
-	RearLeftDoor      string        `json:"rear_left_door,omitempty"`
-	RearLeftMudguard  string        `json:"rear_left_mudguard,omitempty"`
-	RearBumper        string        `json:"rear_bumper,omitempty"`
-	Images            []string      `json:"images,omitempty"`
+	ID                string                       `json:"id,omitempty"`
+	Status            string                       `json:"status,omitempty"`
+	Owner             OwnerResponse                `json:"owner,omitempty"`
+	Organization      *OrganizationSummaryResponse `json:"organization,omitempty"`
+	IsFavorite        *bool                        `json:"is_favorite,omitempty"`
+	PriceHistory      []PricePointResponse         `json:"price_history,omitempty"`
+	Title             string                       `json:"title,omitempty"`
+	Description       string                       `json:"description,omitempty"`
+	Currency          string                       `json:"currency,omitempty"`
+	Price             float64                      `json:"price,omitempty"`
+	City              string                       `json:"city,omitempty"`
+	District          string                       `json:"district,omitempty"`
+	Neighborhood      string                       `json:"neighborhood,omitempty"`
+	ListingNumber     string                       `json:"listing_number,omitempty"`
+	ListingDate       time.Time                    `json:"listing_date,omitempty"`
+	Brand             string                       `json:"brand,omitempty"`
+	Series            string                       `json:"series,omitempty"`
+	Model             string                       `json:"model,omitempty"`
+	Year              int                          `json:"year,omitempty"`
+	FuelType          string                       `json:"fuel_type,omitempty"`
+	Transmission      string                       `json:"transmission,omitempty"`
+	Mileage           int                          `json:"mileage,omitempty"`
+	BodyType          string                       `json:"body_type,omitempty"`
+	EnginePower       int                          `json:"engine_power,omitempty"`
+	EngineVolume      int                          `json:"engine_volume,omitempty"`
+	DriveType         string                       `json:"drive_type,omitempty"`
+	Color             string                       `json:"color,omitempty"`
+	Warranty          bool                         `json:"warranty,omitempty"`
+	HeavyDamage       bool                         `json:"heavy_damage,omitempty"`
+	SellerType        string                       `json:"seller_type,omitempty"`
+	TradeOption       bool                         `json:"trade_option,omitempty"`
+	FrontBumper       string                       `json:"front_bumper,omitempty"`
+	FrontHood         string                       `json:"front_hood,omitempty"`
+	Roof              string                       `json:"roof,omitempty"`
+	FrontRightDoor    string                       `json:"front_right_door,omitempty"`
+	RearRightDoor     string                       `json:"rear_right_door,omitempty"`
+	FrontLeftMudguard string                       `json:"front_left_mudguard,omitempty"`
+	FrontLeftDoor     string                       `json:"front_left_door,omitempty"`
+	RearLeftDoor      string                       `json:"rear_left_door,omitempty"`
+	RearLeftMudguard  string                       `json:"rear_left_mudguard,omitempty"`
+	RearBumper        string                       `json:"rear_bumper,omitempty"`
+	Images            []string                     `json:"images,omitempty"`
 }
 
 type OwnerResponse struct {
-	Id          string    `json:"id,omitempty"`
-	FirstName   string    `json:"first_name,omitempty"`
-	LastName    string    `json:"last_name,omitempty"`
-	CountryCode string    `json:"country_code,omitempty"`
-	PhoneNumber string    `json:"phone_number,omitempty"`
-	CreatedAt   time.Time `json:"created_at,omitempty"`
+	Id            string    `json:"id,omitempty"`
+	FirstName     string    `json:"first_name,omitempty"`
+	LastName      string    `json:"last_name,omitempty"`
+	PhoneVerified bool      `json:"phone_verified"`
+	CreatedAt     time.Time `json:"created_at,omitempty"`
+}
+
+type ContactRevealResponse struct {
+	PhoneNumber string     `json:"phone_number"`
+	Extension   string     `json:"extension,omitempty"`
+	Masked      bool       `json:"masked"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+type AdminUserResponse struct {
+	ID          string    `json:"id"`
+	FirstName   string    `json:"first_name"`
+	LastName    string    `json:"last_name"`
+	ImageUrl    string    `json:"image_url"`
+	CountryCode string    `json:"country_code"`
+	PhoneNumber string    `json:"phone_number"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	LastLogin   time.Time `json:"last_login"`
+}
+
+type UserStatusChangeResponse struct {
+	ChangedBy string    `json:"changed_by"`
+	OldStatus string    `json:"old_status"`
+	NewStatus string    `json:"new_status"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AdminUserDetailResponse struct {
+	User          AdminUserResponse          `json:"user"`
+	Cars          []ListCarResponse          `json:"cars"`
+	StatusHistory []UserStatusChangeResponse `json:"status_history"`
+}
+
+type UserStatusChangeRequest struct {
+	Status string `json:"status" validate:"required,account_status"`
+	Reason string `json:"reason" validate:"required,min=5,max=500"`
+}
+
+type UserRoleChangeRequest struct {
+	Role string `json:"role" validate:"required,user_role"`
 }
@@ -1,25 +1,94 @@
 package carwise
 
 import (
+	"bytes"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"math/big"
 	"mime/multipart"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	passwordResetTTL             = 30 * time.Minute
+	passwordResetMaxAttempts     = 5
+	emailVerificationTTL         = 24 * time.Hour
+	emailChangeTTL               = 24 * time.Hour
+	accountDeletionGracePeriod   = 30 * 24 * time.Hour
+	accountExportPageSize        = 100
+	accountDeletionCodeLength    = 6
+	accountDeletionCodeTTL       = 15 * time.Minute
+	accountDeletionMaxAttempts   = 5
+	phoneRelayTTL                = 7 * 24 * time.Hour
+	organizationInvitationTTL    = 7 * 24 * time.Hour
+	listingLifetime              = 60 * 24 * time.Hour
+	importMaxRows                = 5000
+	importJobTimeout             = time.Hour
+	savedSearchLimit             = 20
+	searchAlertMaxCars           = 20
+	messageAttachmentLimit       = 5
+	messageNotificationDelay     = 15 * time.Minute
+	responseRateMinConversations = 3
+	offerTTL                     = 48 * time.Hour
+	eventTicketTTL               = 30 * time.Second
+	messagePreviewLength         = 140
+	listingExpiryReminder        = 3 * 24 * time.Hour
+	oauthStateTTL                = 10 * time.Minute
+	emailVerificationResendLimit = 3
+	emailVerificationResendTTL   = time.Hour
+	phoneOTPLength               = 6
+	phoneOTPTTL                  = 5 * time.Minute
+	phoneOTPMaxAttempts          = 5
+	phoneOTPSendLimit            = 3
+	phoneOTPSendWindow           = time.Hour
+	totpIssuer                   = "Carwise"
+	totpDigits                   = 6
+	totpPeriod                   = 30
+	totpSkew                     = 1
+	twoFactorChallengeTTL        = 5 * time.Minute
+	twoFactorMaxAttempts         = 5
+	recoveryCodeCount            = 10
+	loginFailureWindow           = 30 * time.Minute
+	loginDelayThreshold          = 3
+	loginBaseDelay               = 5 * time.Second
+	loginAccountLockThreshold    = 10
+	loginAccountLockTTL          = 30 * time.Minute
+	loginIPLockThreshold         = 50
+	loginIPLockTTL               = 30 * time.Minute
+	invalidCredentialsMessage    = "Invalid email or password."
+	tooManyLoginAttemptsMessage  = "Too many failed login attempts. Please try again later."
+)
+
+var dummyPasswordHash, _ = hashPassword("carwise-dummy-password")
+
 type Interactor struct {
 	services Services
+	config   Config
 }
 
-func NewInteractor(svcs Services) *Interactor {
+func NewInteractor(svcs Services, cfg Config) *Interactor {
 	return &Interactor{
 		services: svcs,
+		config:   cfg,
 	}
 }
 
@@ -29,281 +98,4601 @@ func (i *Interactor) CreateUser(request UserCreateRequest) (*User, []string) {
 		return nil, []string{"Email is already in use."}
 	}
 
+	countryCode, phoneNumber, err := normalizePhoneNumber(request.CountryCode, request.PhoneNumber)
+	if err != nil {
+		return nil, []string{"Invalid phone number."}
+	}
+
 	hashedPassword, err := hashPassword(request.Password)
 	if err != nil {
 		return nil, []string{"Failed to hash password."}
 	}
+	locale := request.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
 	user := &User{
-		ID:           uuid.New().String(),
-		FirstName:    request.FirstName,
-		LastName:     request.LastName,
-		CountryCode:  request.CountryCode,
-		PhoneNumber:  request.PhoneNumber,
-		Email:        request.Email,
-		PasswordHash: hashedPassword,
-		Role:         UserRoleRegular,
-		Status:       AccountStatusActive,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-		LastLogin:    time.Now(),
+		ID:              uuid.New().String(),
+		FirstName:       request.FirstName,
+		LastName:        request.LastName,
+		CountryCode:     countryCode,
+		PhoneNumber:     phoneNumber,
+		Email:           request.Email,
+		PasswordHash:    hashedPassword,
+		Role:            UserRoleRegular,
+		Status:          AccountStatusActive,
+		EmailVerified:   false,
+		Locale:          locale,
+		ShowPhoneNumber: true,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		LastLogin:       time.Now(),
 	}
 
 	err = i.services.UserRepo.Create(user)
 	if err != nil {
 		return nil, []string{"Failed to create user: " + err.Error()}
 	}
+
+	err = i.sendVerificationEmail(user)
+	if err != nil {
+		log.Printf("Error sending verification email: %v\n", err)
+	}
+
 	return user, nil
 }
 
-func (i *Interactor) LoginUser(request UserLoginRequest) (*User, []string) {
+func (i *Interactor) VerifyEmail(request VerifyEmailRequest) []string {
+	verify, err := i.services.EmailVerificationRepo.VerifyVerificationCode(request.Email, hashCode(request.Token))
+	if err != nil {
+		log.Printf("Error verifying email token: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if !verify {
+		return []string{"Invalid or expired email verification token."}
+	}
+
 	user, err := i.services.UserRepo.GetByEmail(request.Email)
 	if err != nil {
-		return nil, []string{err.Error()}
+		log.Printf("Error fetching user by email: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
 	}
 
-	if !comparePasswords(user.PasswordHash, request.Password) {
-		return nil, []string{"invalid credentials"}
+	user.EmailVerified = true
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		log.Printf("Error marking email as verified: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
 	}
 
-	return user, nil
+	err = i.services.EmailVerificationRepo.DeleteVerificationCode(request.Email)
+	if err != nil {
+		log.Printf("Error deleting email verification token: %v\n", err)
+	}
+
+	return nil
 }
 
-func (i *Interactor) IsTokenBlackListed(token string) (bool, []string) {
-	isBlacklisted, err := i.services.TokenRepo.IsTokenBlackListed(token)
+func (i *Interactor) ResendVerificationEmail(userId string) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
 	if err != nil {
-		return false, []string{"Failed to check token blacklist: " + err.Error()}
+		return []string{err.Error()}
 	}
 
-	return isBlacklisted, nil
-}
+	if user.EmailVerified {
+		return []string{"Email is already verified."}
+	}
 
-func (i *Interactor) AddTokenBlackList(token string) []string {
-	err := i.services.TokenRepo.AddTokenBlackList(token)
+	count, err := i.services.EmailVerificationRepo.IncrementResendCount(user.Email, emailVerificationResendTTL)
 	if err != nil {
-		return []string{"Failed to add token to blacklist: " + err.Error()}
+		log.Printf("Error counting verification email resends: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if count > emailVerificationResendLimit {
+		return []string{"Too many verification emails requested. Please try again later."}
+	}
+
+	err = i.sendVerificationEmail(user)
+	if err != nil {
+		log.Printf("Error sending verification email: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
 	}
 
 	return nil
 }
 
-func (i *Interactor) GetBrands() ([]BrandResponse, error) {
-	brands, err := i.services.AuxRepo.GetBrands()
+func (i *Interactor) sendVerificationEmail(user *User) error {
+	token, err := generateToken(40)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching brands: %w", err)
+		return fmt.Errorf("failed to generate email verification token: %w", err)
 	}
 
-	var brandResponses []BrandResponse
+	err = i.services.EmailVerificationRepo.SaveVerificationCode(user.Email, hashCode(token), emailVerificationTTL)
+	if err != nil {
+		return err
+	}
 
-	for _, brand := range brands {
+	verifyLink := fmt.Sprintf("%s/verify-email?token=%s&email=%s", i.config.FrontendURL, url.QueryEscape(token), url.QueryEscape(user.Email))
+	return i.sendEmail(user, EmailTemplateVerifyEmail, map[string]interface{}{
+		"Link": verifyLink,
+	})
+}
 
-		series, err := i.services.AuxRepo.GetSeriesByBrand(brand.ID)
+func (i *Interactor) LoginUser(request UserLoginRequest, ip, userAgent string) (*User, []string) {
+	accountKey := "account:" + strings.ToLower(request.Email)
+	ipKey := "ip:" + ip
+	event := &LoginEvent{Email: request.Email, IP: ip, UserAgent: userAgent, Method: LoginMethodPassword}
+
+	user, err := i.services.UserRepo.GetByEmail(request.Email)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	for _, key := range []string{accountKey, ipKey} {
+		lockedFor, err := i.services.LoginAttemptRepo.LockedFor(key)
 		if err != nil {
-			return nil, fmt.Errorf("error fetching series for brand %d: %w", brand.ID, err)
+			log.Printf("Error checking login lock: %v\n", err)
+			continue
+		}
+		if lockedFor > 0 {
+			i.recordLoginEvent(event, user, LoginOutcomeFailure, LoginReasonLocked)
+			return nil, []string{tooManyLoginAttemptsMessage}
 		}
+	}
 
-		var seriesResponses []SeriesResponse
-		for _, s := range series {
+	if user == nil {
+		comparePasswords(dummyPasswordHash, request.Password)
+		i.recordLoginFailure(accountKey, ipKey, nil)
+		i.recordLoginEvent(event, nil, LoginOutcomeFailure, LoginReasonUnknownAccount)
+		return nil, []string{invalidCredentialsMessage}
+	}
 
-			models, err := i.services.AuxRepo.GetModelsBySeries(s.ID)
-			if err != nil {
-				return nil, fmt.Errorf("error fetching models for series %d: %w", s.ID, err)
-			}
+	if !comparePasswords(user.PasswordHash, request.Password) {
+		i.recordLoginFailure(accountKey, ipKey, user)
+		i.recordLoginEvent(event, user, LoginOutcomeFailure, LoginReasonInvalidPassword)
+		return nil, []string{invalidCredentialsMessage}
+	}
 
-			var modelResponses []ModelResponse
-			for _, model := range models {
-				modelResponses = append(modelResponses, ModelResponse{
-					Id:    model.ID,
-					Name:  model.Name,
-					Count: len(models),
-				})
-			}
+	err = i.services.LoginAttemptRepo.ResetFailures(accountKey)
+	if err != nil {
+		log.Printf("Error resetting login failures: %v\n", err)
+	}
 
-			seriesResponses = append(seriesResponses, SeriesResponse{
-				Id:     s.ID,
-				Name:   s.Name,
-				Count:  len(models),
-				Models: modelResponses,
-			})
-		}
+	if errors := i.checkLoginStatus(event, user); errors != nil {
+		return nil, errors
+	}
 
-		brandResponses = append(brandResponses, BrandResponse{
-			Id:     brand.ID,
-			Logo:   brand.Logo,
-			Name:   brand.Name,
-			Count:  len(series),
-			Series: seriesResponses,
-		})
+	if user.TwoFactorEnabled {
+		i.recordLoginEvent(event, user, LoginOutcomeChallenge, LoginReasonTwoFactorRequired)
+	} else {
+		i.completeLogin(event, user)
 	}
 
-	return brandResponses, nil
+	return user, nil
 }
 
-func (i *Interactor) ResetPasswordRequest(request ResetPasswordRequest) []string {
-	existingUser, err := i.services.UserRepo.GetByEmail(request.Email)
+func (i *Interactor) checkLoginStatus(event *LoginEvent, user *User) []string {
+	if user.Status == AccountStatusBanned {
+		i.recordLoginEvent(event, user, LoginOutcomeFailure, LoginReasonBanned)
+		return []string{"This account has been banned."}
+	}
+	if user.Status == AccountStatusInactive {
+		i.recordLoginEvent(event, user, LoginOutcomeFailure, LoginReasonSuspended)
+		return []string{"This account has been suspended."}
+	}
+	return nil
+}
+
+func (i *Interactor) completeLogin(event *LoginEvent, user *User) {
+	i.recordLoginEvent(event, user, LoginOutcomeSuccess, "")
+
+	user.LastLogin = time.Now()
+	err := i.services.UserRepo.UpdateLastLogin(user.ID, user.LastLogin)
 	if err != nil {
-		log.Printf("Error fetching user by email: %v\n", err)
-		return []string{"An unexpected error occurred. Please try again later."}
+		log.Printf("Error updating last login: %v\n", err)
 	}
+}
 
-	if existingUser == nil {
-		return []string{"No account found with this email."}
+func (i *Interactor) recordLoginEvent(event *LoginEvent, user *User, outcome, reason string) {
+	record := *event
+	record.ID = uuid.New().String()
+	record.Outcome = outcome
+	record.Reason = reason
+	record.CreatedAt = time.Now()
+	if user != nil {
+		record.UserID = user.ID
+		record.Email = user.Email
 	}
-	token, err := generateToken(40)
+
+	err := i.services.LoginEventRepo.Create(&record)
 	if err != nil {
-		log.Printf("Error generate password reset token: %v\n", err)
-		return []string{"An unexpected error occurred. Please try again later."}
+		log.Printf("Error recording login event: %v\n", err)
+	}
+}
+
+func (i *Interactor) GetSecurityEvents(userId string, page, limit int) ([]SecurityEventResponse, []string) {
+	events, err := i.services.LoginEventRepo.Search("", userId, "", page, limit)
+	if err != nil {
+		log.Printf("Error fetching login events: %v\n", err)
+		return nil, []string{"failed to fetch security events"}
+	}
+
+	response := []SecurityEventResponse{}
+	for _, event := range events {
+		response = append(response, toSecurityEventResponse(event))
 	}
-	err = i.services.PasswordResetRepo.SaveResetCode(request.Email, token, 5*24*time.Hour)
+
+	return response, nil
+}
+
+func (i *Interactor) SearchLoginEvents(query, userId, outcome string, page, limit int) ([]AdminLoginEventResponse, []string) {
+	events, err := i.services.LoginEventRepo.Search(query, userId, outcome, page, limit)
 	if err != nil {
-		fmt.Printf("Failed to save reset code: %v\n", err)
+		log.Printf("Error searching login events: %v\n", err)
+		return nil, []string{"failed to search login events"}
 	}
 
-	resetLink := fmt.Sprintf("http://localhost:3000/reset-password?token=%s&email=%s", token, request.Email)
-	emailBody := fmt.Sprintf(`From: Carwise <app.carwise@gmail.com>
-Subject: Password Reset Request
-Dear User,
-We received a request to reset the password associated with your account. If you made this request, please click the link below to reset your password:
+	response := []AdminLoginEventResponse{}
+	for _, event := range events {
+		response = append(response, AdminLoginEventResponse{
+			ID:                    event.ID,
+			UserID:                event.UserID,
+			Email:                 event.Email,
+			SecurityEventResponse: toSecurityEventResponse(event),
+		})
+	}
 
-%s
+	return response, nil
+}
 
-This link will expire in 5 days. If you did not request a password reset, you can safely ignore this email.
+func toSecurityEventResponse(event LoginEvent) SecurityEventResponse {
+	return SecurityEventResponse{
+		Method:    event.Method,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		CreatedAt: event.CreatedAt,
+	}
+}
 
-Best regards,
-Carwise Team`, resetLink)
+func (i *Interactor) recordLoginFailure(accountKey, ipKey string, user *User) {
+	failures, err := i.services.LoginAttemptRepo.RecordFailure(accountKey, loginFailureWindow)
+	if err != nil {
+		log.Printf("Error recording login failure: %v\n", err)
+	} else if failures >= loginAccountLockThreshold {
+		err = i.services.LoginAttemptRepo.Lock(accountKey, loginAccountLockTTL)
+		if err != nil {
+			log.Printf("Error locking account: %v\n", err)
+		}
+		err = i.services.LoginAttemptRepo.ResetFailures(accountKey)
+		if err != nil {
+			log.Printf("Error resetting login failures: %v\n", err)
+		}
+		if user != nil {
+			err = i.sendAccountLockedEmail(user)
+			if err != nil {
+				log.Printf("Error sending account locked email: %v\n", err)
+			}
+		}
+	} else if failures >= loginDelayThreshold {
+		delay := loginBaseDelay << (failures - loginDelayThreshold)
+		err = i.services.LoginAttemptRepo.Lock(accountKey, delay)
+		if err != nil {
+			log.Printf("Error delaying login attempts: %v\n", err)
+		}
+	}
 
-	err = i.services.MailGW.Send(request.Email, []byte(emailBody))
+	failures, err = i.services.LoginAttemptRepo.RecordFailure(ipKey, loginFailureWindow)
 	if err != nil {
-		log.Printf("Error send password reset email: %v\n", err)
-		return []string{"An unexpected error occurred. Please try again later."}
+		log.Printf("Error recording login failure: %v\n", err)
+	} else if failures >= loginIPLockThreshold {
+		err = i.services.LoginAttemptRepo.Lock(ipKey, loginIPLockTTL)
+		if err != nil {
+			log.Printf("Error locking IP address: %v\n", err)
+		}
+		err = i.services.LoginAttemptRepo.ResetFailures(ipKey)
+		if err != nil {
+			log.Printf("Error resetting login failures: %v\n", err)
+		}
 	}
+}
 
-	return nil
+func (i *Interactor) sendAccountLockedEmail(user *User) error {
+	return i.sendEmail(user, EmailTemplateAccountLocked, map[string]interface{}{
+		"Minutes": int(loginAccountLockTTL.Minutes()),
+	})
 }
 
-func (i *Interactor) ChangePassword(request ChangePasswordRequest, token, email string) []string {
-	verify, err := i.services.PasswordResetRepo.VerifyResetCode(email, token)
+func (i *Interactor) StartSocialLogin(provider string) (string, []string) {
+	identityProvider, ok := i.services.IdentityProviders[provider]
+	if !ok {
+		return "", []string{"Unsupported login provider."}
+	}
+
+	state, err := generateToken(32)
 	if err != nil {
-		log.Printf("Error verifying reset token: %v\n", err)
-		return []string{"An unexpected error occurred. Please try again later."}
+		return "", []string{"Failed to generate login state."}
 	}
-	if !verify {
-		return []string{"Invalid or expired password reset token."}
+	codeVerifier, err := generatePKCEVerifier()
+	if err != nil {
+		return "", []string{"Failed to generate login state."}
+	}
+	nonce, err := generateToken(32)
+	if err != nil {
+		return "", []string{"Failed to generate login state."}
 	}
 
-	hashedPassword, err := hashPassword(request.Password)
+	err = i.services.OAuthStateRepo.SaveState(state, &OAuthState{
+		Provider:     provider,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+	}, oauthStateTTL)
 	if err != nil {
-		log.Printf("Error hashing password: %v\n", err)
-		return []string{"An unexpected error occurred. Please try again later."}
+		log.Printf("Error saving oauth state: %v\n", err)
+		return "", []string{"An unexpected error occurred. Please try again later."}
 	}
 
-	err = i.services.UserRepo.UpdatePassword(email, hashedPassword)
+	return identityProvider.AuthCodeURL(state, codeVerifier, nonce), nil
+}
+
+func (i *Interactor) SocialLogin(provider string, request SocialLoginRequest, ip, userAgent string) (*User, []string) {
+	identityProvider, ok := i.services.IdentityProviders[provider]
+	if !ok {
+		return nil, []string{"Unsupported login provider."}
+	}
+
+	state, err := i.services.OAuthStateRepo.ConsumeState(request.State)
 	if err != nil {
-		log.Printf("Error updating password: %v\n", err)
-		return []string{"An unexpected error occurred. Please try again later."}
+		log.Printf("Error fetching oauth state: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if state == nil || state.Provider != provider {
+		return nil, []string{"Invalid or expired login request."}
 	}
 
-	err = i.services.PasswordResetRepo.DeleteResetCode(email)
+	external, err := identityProvider.Exchange(request.Code, state.CodeVerifier, state.Nonce)
 	if err != nil {
-		log.Printf("Error deleting reset token: %v\n", err)
+		log.Printf("Error exchanging authorization code with %s: %v\n", provider, err)
+		return nil, []string{"Could not sign in with " + provider + "."}
 	}
+	external.Provider = provider
 
-	return nil
-}
+	user, errors := i.findOrCreateSocialUser(external)
+	if errors != nil {
+		return nil, errors
+	}
 
-func (i *Interactor) GetProfile(id string) (*ProfileResponse, []string) {
-	user, err := i.services.UserRepo.GetByID(id)
-	if err != nil {
-		return nil, []string{err.Error()}
+	event := &LoginEvent{IP: ip, UserAgent: userAgent, Method: LoginMethodSocial + ":" + provider}
+	if errors := i.checkLoginStatus(event, user); errors != nil {
+		return nil, errors
 	}
-	return &ProfileResponse{
-		ID:          user.ID,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		ImageUrl:    user.ImageUrl,
-		CountryCode: user.CountryCode,
-		PhoneNumber: user.PhoneNumber,
-		Email:       user.Email,
-		Role:        user.Role,
-		Status:      user.Status,
-		CreatedAt:   user.CreatedAt,
-	}, nil
+
+	if user.TwoFactorEnabled {
+		i.recordLoginEvent(event, user, LoginOutcomeChallenge, LoginReasonTwoFactorRequired)
+	} else {
+		i.completeLogin(event, user)
+	}
+
+	return user, nil
 }
 
-func (i *Interactor) EditProfile(userId string, request ProfileEditRequest, avatar *multipart.FileHeader) []string {
-	var errors []string
-	user, err := i.services.UserRepo.GetByID(userId)
+func (i *Interactor) findOrCreateSocialUser(external *ExternalIdentity) (*User, []string) {
+	identity, err := i.services.UserIdentityRepo.GetByProviderSubject(external.Provider, external.Subject)
 	if err != nil {
-		errors = append(errors, fmt.Sprintf("Failed to get user: %v", err))
-		return errors
+		log.Printf("Error fetching user identity: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if identity != nil {
+		user, err := i.services.UserRepo.GetByID(identity.UserID)
+		if err != nil {
+			log.Printf("Error fetching user by id: %v\n", err)
+			return nil, []string{"An unexpected error occurred. Please try again later."}
+		}
+		return user, nil
 	}
 
-	user.FirstName = request.FirstName
-	user.LastName = request.LastName
-	user.CountryCode = request.CountryCode
-	user.PhoneNumber = request.PhoneNumber
+	if external.Email == "" || !external.EmailVerified {
+		return nil, []string{"Your " + external.Provider + " account has no verified email address."}
+	}
 
-	err = i.services.UserRepo.Update(user)
-	if err != nil {
-		errors = append(errors, fmt.Sprintf("Failed to update user profile: %v", err))
-		return errors
+	user, err := i.services.UserRepo.GetByEmail(external.Email)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
 	}
 
-	if avatar != nil {
-		file, err := avatar.Open()
+	if user != nil && !user.EmailVerified {
+		// the existing password was never proven to belong to the owner of this mailbox
+		user.PasswordHash, err = unusablePasswordHash()
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to open avatar file: %v", err))
-			return errors
+			return nil, []string{"Failed to hash password."}
 		}
-		defer file.Close()
-
-		avatarURL, err := i.services.CDNRepo.SaveUserAvatar(userId, file)
+		err = i.services.UserRepo.UpdatePassword(user.Email, user.PasswordHash)
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to upload avatar: %v", err))
-			return errors
+			log.Printf("Error resetting password: %v\n", err)
+			return nil, []string{"An unexpected error occurred. Please try again later."}
 		}
-
-		user.ImageUrl = avatarURL
+		user.EmailVerified = true
 		err = i.services.UserRepo.Update(user)
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("Failed to update user avatar URL: %v", err))
-			return errors
+			log.Printf("Error updating user: %v\n", err)
+			return nil, []string{"An unexpected error occurred. Please try again later."}
+		}
+		err = i.services.TokenRepo.RevokeUserTokens(user.ID, time.Now())
+		if err != nil {
+			log.Printf("Error revoking user tokens: %v\n", err)
 		}
 	}
 
-	return nil
+	if user == nil {
+		passwordHash, err := unusablePasswordHash()
+		if err != nil {
+			return nil, []string{"Failed to hash password."}
+		}
+		user = &User{
+			ID:              uuid.New().String(),
+			FirstName:       external.FirstName,
+			LastName:        external.LastName,
+			ImageUrl:        external.ImageUrl,
+			Email:           external.Email,
+			PasswordHash:    passwordHash,
+			Role:            UserRoleRegular,
+			Status:          AccountStatusActive,
+			EmailVerified:   true,
+			Locale:          DefaultLocale,
+			ShowPhoneNumber: true,
+			CreatedAt:       time.Now(),
+			UpdatedAt:       time.Now(),
+			LastLogin:       time.Now(),
+		}
+		err = i.services.UserRepo.Create(user)
+		if err != nil {
+			return nil, []string{"Failed to create user: " + err.Error()}
+		}
+	}
+
+	err = i.services.UserIdentityRepo.Create(&UserIdentity{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Provider:  external.Provider,
+		Subject:   external.Subject,
+		Email:     external.Email,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error linking user identity: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return user, nil
 }
 
-func (i *Interactor) CreateCar(userId string, request CarCreateRequest) []string {
-	request.OwnerId = userId
-	request.ID = uuid.New().String()
-	request.ListingDate = time.Now()
-	var err error
-	request.ListingNumber, err = generateSecureListingNumber(10)
+func (i *Interactor) SetupTwoFactor(userId string) (*TwoFactorSetupResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(userId)
 	if err != nil {
-		return []string{err.Error()}
+		return nil, []string{err.Error()}
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, []string{"Two-factor authentication is already enabled."}
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		log.Printf("Error generating TOTP secret: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	user.TwoFactorSecret = secret
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		log.Printf("Error saving TOTP secret: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return &TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: totpURI(secret, user.Email),
+	}, nil
+}
+
+func (i *Interactor) ConfirmTwoFactor(userId string, request TwoFactorCodeRequest) (*TwoFactorRecoveryCodesResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, []string{"Two-factor authentication is already enabled."}
+	}
+	if user.TwoFactorSecret == "" {
+		return nil, []string{"Two-factor authentication setup has not been started."}
+	}
+
+	if !i.verifyTOTPCode(user, request.Code) {
+		return nil, []string{"Invalid authentication code."}
+	}
+
+	codes, hashes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		log.Printf("Error generating recovery codes: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.RecoveryCodeRepo.ReplaceRecoveryCodes(userId, hashes)
+	if err != nil {
+		log.Printf("Error saving recovery codes: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	user.TwoFactorEnabled = true
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		log.Printf("Error enabling two-factor authentication: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return &TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (i *Interactor) DisableTwoFactor(userId string, request TwoFactorDisableRequest) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !user.TwoFactorEnabled {
+		return []string{"Two-factor authentication is not enabled."}
+	}
+
+	if !comparePasswords(user.PasswordHash, request.Password) {
+		return []string{"invalid credentials"}
+	}
+
+	if !i.verifySecondFactor(user, request.Code) {
+		return []string{"Invalid authentication code."}
+	}
+
+	user.TwoFactorEnabled = false
+	user.TwoFactorSecret = ""
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		log.Printf("Error disabling two-factor authentication: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.RecoveryCodeRepo.DeleteRecoveryCodes(userId)
+	if err != nil {
+		log.Printf("Error deleting recovery codes: %v\n", err)
+	}
+
+	return nil
+}
+
+func (i *Interactor) CreateTwoFactorChallenge(userId string) (string, []string) {
+	token, err := generateToken(32)
+	if err != nil {
+		log.Printf("Error generating two-factor challenge: %v\n", err)
+		return "", []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.TwoFactorRepo.SaveChallenge(token, userId, twoFactorChallengeTTL)
+	if err != nil {
+		log.Printf("Error saving two-factor challenge: %v\n", err)
+		return "", []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return token, nil
+}
+
+func (i *Interactor) LoginTwoFactor(request TwoFactorLoginRequest, ip, userAgent string) (*User, []string) {
+	event := &LoginEvent{IP: ip, UserAgent: userAgent, Method: LoginMethodTwoFactor}
+
+	userId, err := i.services.TwoFactorRepo.GetChallenge(request.ChallengeToken)
+	if err != nil {
+		log.Printf("Error fetching two-factor challenge: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if userId == "" {
+		return nil, []string{"Invalid or expired login challenge."}
+	}
+
+	attempts, err := i.services.TwoFactorRepo.IncrementChallengeAttempts(request.ChallengeToken)
+	if err != nil {
+		log.Printf("Error counting two-factor attempts: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	if attempts > twoFactorMaxAttempts {
+		err = i.services.TwoFactorRepo.DeleteChallenge(request.ChallengeToken)
+		if err != nil {
+			log.Printf("Error deleting two-factor challenge: %v\n", err)
+		}
+		i.recordLoginEvent(event, user, LoginOutcomeFailure, LoginReasonLocked)
+		return nil, []string{"Too many failed attempts. Please log in again."}
+	}
+
+	if !i.verifySecondFactor(user, request.Code) {
+		i.recordLoginEvent(event, user, LoginOutcomeFailure, LoginReasonInvalidCode)
+		return nil, []string{"Invalid authentication code."}
+	}
+
+	err = i.services.TwoFactorRepo.DeleteChallenge(request.ChallengeToken)
+	if err != nil {
+		log.Printf("Error deleting two-factor challenge: %v\n", err)
+	}
+
+	i.completeLogin(event, user)
+
+	return user, nil
+}
+
+func (i *Interactor) verifySecondFactor(user *User, code string) bool {
+	if i.verifyTOTPCode(user, code) {
+		return true
+	}
+
+	used, err := i.services.RecoveryCodeRepo.UseRecoveryCode(user.ID, hashCode(normalizeRecoveryCode(code)))
+	if err != nil {
+		log.Printf("Error checking recovery code: %v\n", err)
+		return false
+	}
+
+	return used
+}
+
+func (i *Interactor) verifyTOTPCode(user *User, code string) bool {
+	step, ok := validateTOTP(user.TwoFactorSecret, code, time.Now())
+	if !ok {
+		return false
+	}
+
+	fresh, err := i.services.TwoFactorRepo.MarkCodeUsed(user.ID, step, time.Duration(2*totpSkew+1)*totpPeriod*time.Second)
+	if err != nil {
+		log.Printf("Error recording used TOTP code: %v\n", err)
+		return false
+	}
+
+	return fresh
+}
+
+func (i *Interactor) IsTokenBlackListed(token string) (bool, []string) {
+	isBlacklisted, err := i.services.TokenRepo.IsTokenBlackListed(token)
+	if err != nil {
+		return false, []string{"Failed to check token blacklist: " + err.Error()}
+	}
+
+	return isBlacklisted, nil
+}
+
+func (i *Interactor) AddTokenBlackList(token string) []string {
+	err := i.services.TokenRepo.AddTokenBlackList(token)
+	if err != nil {
+		return []string{"Failed to add token to blacklist: " + err.Error()}
+	}
+
+	return nil
+}
+
+func (i *Interactor) IsTokenRevoked(userId string, issuedAt int64) (bool, []string) {
+	revokedAt, err := i.services.TokenRepo.GetUserTokensRevokedAt(userId)
+	if err != nil {
+		return false, []string{"Failed to check token revocation: " + err.Error()}
+	}
+
+	return !revokedAt.IsZero() && issuedAt < revokedAt.Unix(), nil
+}
+
+func (i *Interactor) AllowRequest(policy RateLimitPolicy, subject string) (*RateLimitResult, []string) {
+	key := policy.Name + ":" + subject
+	result, err := i.services.RateLimitRepo.Allow(key, policy.Limit, policy.Window)
+	if err != nil {
+		return nil, []string{"Failed to check rate limit: " + err.Error()}
+	}
+
+	return result, nil
+}
+
+func (i *Interactor) GetBrands() ([]BrandResponse, error) {
+	brands, err := i.services.AuxRepo.GetBrands()
+	if err != nil {
+		return nil, fmt.Errorf("error fetching brands: %w", err)
+	}
+
+	var brandResponses []BrandResponse
+
+	for _, brand := range brands {
+
+		series, err := i.services.AuxRepo.GetSeriesByBrand(brand.ID)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching series for brand %d: %w", brand.ID, err)
+		}
+
+		var seriesResponses []SeriesResponse
+		for _, s := range series {
+
+			models, err := i.services.AuxRepo.GetModelsBySeries(s.ID)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching models for series %d: %w", s.ID, err)
+			}
+
+			var modelResponses []ModelResponse
+			for _, model := range models {
+				modelResponses = append(modelResponses, ModelResponse{
+					Id:    model.ID,
+					Name:  model.Name,
+					Count: len(models),
+				})
+			}
+
+			seriesResponses = append(seriesResponses, SeriesResponse{
+				Id:     s.ID,
+				Name:   s.Name,
+				Count:  len(models),
+				Models: modelResponses,
+			})
+		}
+
+		brandResponses = append(brandResponses, BrandResponse{
+			Id:     brand.ID,
+			Logo:   brand.Logo,
+			Name:   brand.Name,
+			Count:  len(series),
+			Series: seriesResponses,
+		})
+	}
+
+	return brandResponses, nil
+}
+
+// ResetPasswordRequest answers the same way, and just as fast, whether or not
+// an account uses the email address. The lookup and the email happen in
+// the background, so neither timing nor a failed send gives it away.
+func (i *Interactor) ResetPasswordRequest(request ResetPasswordRequest) []string {
+	go i.sendPasswordReset(request.Email)
+	return nil
+}
+
+func (i *Interactor) sendPasswordReset(email string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error sending password reset: %v\n", r)
+		}
+	}()
+
+	existingUser, err := i.services.UserRepo.GetByEmail(email)
+	if errors.Is(err, ErrUserNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return
+	}
+
+	token, err := generateToken(40)
+	if err != nil {
+		log.Printf("Error generate password reset token: %v\n", err)
+		return
+	}
+	err = i.services.PasswordResetRepo.SaveResetCode(existingUser.Email, hashCode(token), passwordResetTTL)
+	if err != nil {
+		log.Printf("Failed to save reset code: %v\n", err)
+		return
+	}
+
+	resetLink := fmt.Sprintf("%s/reset-password?token=%s&email=%s", i.config.FrontendURL, url.QueryEscape(token), url.QueryEscape(existingUser.Email))
+	err = i.sendEmail(existingUser, EmailTemplatePasswordReset, map[string]interface{}{
+		"Link":    resetLink,
+		"Minutes": int(passwordResetTTL.Minutes()),
+	})
+	if err != nil {
+		log.Printf("Error send password reset email: %v\n", err)
+	}
+}
+
+func (i *Interactor) ChangePassword(request ChangePasswordRequest, token, email string) []string {
+	attempts, err := i.services.PasswordResetRepo.IncrementAttempts(email, passwordResetTTL)
+	if err != nil {
+		log.Printf("Error counting reset token attempts: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if attempts > passwordResetMaxAttempts {
+		err = i.services.PasswordResetRepo.DeleteResetCode(email)
+		if err != nil {
+			log.Printf("Error deleting reset token: %v\n", err)
+		}
+		return []string{"Invalid or expired password reset token."}
+	}
+
+	consumed, err := i.services.PasswordResetRepo.ConsumeResetCode(email, hashCode(token))
+	if err != nil {
+		log.Printf("Error consuming reset token: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if !consumed {
+		return []string{"Invalid or expired password reset token."}
+	}
+
+	user, err := i.services.UserRepo.GetByEmail(email)
+	if err != nil {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	hashedPassword, err := hashPassword(request.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.UserRepo.UpdatePassword(email, hashedPassword)
+	if err != nil {
+		log.Printf("Error updating password: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.TokenRepo.RevokeUserTokens(user.ID, time.Now())
+	if err != nil {
+		log.Printf("Error revoking tokens of user %s: %v\n", user.ID, err)
+	}
+
+	return nil
+}
+
+func (i *Interactor) UpdatePassword(userId string, request UpdatePasswordRequest) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !comparePasswords(user.PasswordHash, request.CurrentPassword) {
+		return []string{"Current password is incorrect."}
+	}
+
+	hashedPassword, err := hashPassword(request.Password)
+	if err != nil {
+		log.Printf("Error hashing password: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.UserRepo.UpdatePassword(user.Email, hashedPassword)
+	if err != nil {
+		log.Printf("Error updating password: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.TokenRepo.RevokeUserTokens(user.ID, time.Now())
+	if err != nil {
+		log.Printf("Error revoking tokens of user %s: %v\n", user.ID, err)
+	}
+
+	return nil
+}
+
+func (i *Interactor) RequestEmailChange(userId string, request ChangeEmailRequest) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !comparePasswords(user.PasswordHash, request.Password) {
+		return []string{"Current password is incorrect."}
+	}
+
+	if strings.EqualFold(user.Email, request.NewEmail) {
+		return []string{"New email is the same as the current one."}
+	}
+
+	existingUser, err := i.services.UserRepo.GetByEmail(request.NewEmail)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if existingUser != nil {
+		return []string{"Email is already in use."}
+	}
+
+	token, err := generateToken(40)
+	if err != nil {
+		log.Printf("Error generating email change token: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.EmailChangeRepo.SaveEmailChange(user.ID, &EmailChange{
+		NewEmail: request.NewEmail,
+		CodeHash: hashCode(token),
+	}, emailChangeTTL)
+	if err != nil {
+		log.Printf("Error saving email change: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	confirmLink := fmt.Sprintf("%s/confirm-email-change?token=%s&user_id=%s", i.config.FrontendURL, url.QueryEscape(token), url.QueryEscape(user.ID))
+	email, err := i.renderEmail(user, EmailTemplateEmailChangeConfirm, map[string]interface{}{
+		"Link": confirmLink,
+	})
+	if err != nil {
+		log.Printf("Error rendering email change confirmation: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	email.To = request.NewEmail
+	err = i.services.MailGW.Send(email)
+	if err != nil {
+		log.Printf("Error sending email change confirmation: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.sendEmail(user, EmailTemplateEmailChangeNotice, map[string]interface{}{
+		"NewEmail": request.NewEmail,
+	})
+	if err != nil {
+		log.Printf("Error sending email change notice: %v\n", err)
+	}
+
+	return nil
+}
+
+func (i *Interactor) ConfirmEmailChange(request ConfirmEmailChangeRequest) []string {
+	change, err := i.services.EmailChangeRepo.GetEmailChange(request.UserID)
+	if err != nil {
+		log.Printf("Error fetching email change: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if change == nil || subtle.ConstantTimeCompare([]byte(change.CodeHash), []byte(hashCode(request.Token))) != 1 {
+		return []string{"Invalid or expired email change token."}
+	}
+
+	err = i.services.EmailChangeRepo.DeleteEmailChange(request.UserID)
+	if err != nil {
+		log.Printf("Error deleting email change: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	existingUser, err := i.services.UserRepo.GetByEmail(change.NewEmail)
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if existingUser != nil {
+		return []string{"Email is already in use."}
+	}
+
+	err = i.services.UserRepo.UpdateEmail(request.UserID, change.NewEmail)
+	if err != nil {
+		log.Printf("Error updating email: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) GetUser(id string) (*User, []string) {
+	user, err := i.services.UserRepo.GetByID(id)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	return user, nil
+}
+
+func (i *Interactor) GetProfile(id string) (*ProfileResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(id)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	return &ProfileResponse{
+		ID:                  user.ID,
+		FirstName:           user.FirstName,
+		LastName:            user.LastName,
+		ImageUrl:            user.ImageUrl,
+		CountryCode:         user.CountryCode,
+		PhoneNumber:         user.PhoneNumber,
+		Email:               user.Email,
+		Role:                user.Role,
+		Status:              user.Status,
+		EmailVerified:       user.EmailVerified,
+		PhoneVerified:       user.PhoneVerified,
+		TwoFactorEnabled:    user.TwoFactorEnabled,
+		ShowPhoneNumber:     user.ShowPhoneNumber,
+		Locale:              user.Locale,
+		DeletionScheduledAt: deletionScheduledAt(user),
+		CreatedAt:           user.CreatedAt,
+	}, nil
+}
+
+func deletionScheduledAt(user *User) *time.Time {
+	if user.DeletionRequestedAt.IsZero() {
+		return nil
+	}
+	scheduledAt := user.DeletionRequestedAt.Add(accountDeletionGracePeriod)
+	return &scheduledAt
+}
+
+func (i *Interactor) UpdatePrivacySettings(userId string, request PrivacySettingsRequest) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	user.ShowPhoneNumber = *request.ShowPhoneNumber
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	return nil
+}
+
+func (i *Interactor) getPublicUser(id string) (*User, []string) {
+	user, err := i.services.UserRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, []string{"user not found"}
+		}
+		log.Printf("Error fetching user by id: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	if user.Status == AccountStatusBanned || user.Status == AccountStatusDeleted || !user.DeletionRequestedAt.IsZero() {
+		return nil, []string{"user not found"}
+	}
+
+	return user, nil
+}
+
+func (i *Interactor) GetPublicProfile(id string) (*PublicProfileResponse, []string) {
+	user, errors := i.getPublicUser(id)
+	if errors != nil {
+		return nil, errors
+	}
+
+	stats, err := i.services.CarRepo.GetSellerStats(user.ID)
+	if err != nil {
+		log.Printf("Error fetching seller stats of user %s: %v\n", user.ID, err)
+		return nil, []string{"failed to fetch seller stats"}
+	}
+
+	sellerType := SellerTypeIndividual
+	if stats.HasDealerListings {
+		sellerType = SellerTypeDealer
+	}
+
+	responseStats, err := i.services.ConversationRepo.GetResponseStats(user.ID)
+	if err != nil {
+		log.Printf("Error fetching response stats of user %s: %v\n", user.ID, err)
+		return nil, []string{"failed to fetch seller stats"}
+	}
+
+	response := &PublicProfileResponse{
+		ID:             user.ID,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		ImageUrl:       user.ImageUrl,
+		SellerType:     sellerType,
+		ActiveListings: stats.ActiveListings,
+		SoldListings:   stats.SoldListings,
+		PhoneVerified:  user.PhoneVerified,
+		MemberSince:    user.CreatedAt,
+	}
+	if responseStats.Conversations >= responseRateMinConversations {
+		rate := math.Round(float64(responseStats.Responded)/float64(responseStats.Conversations)*100) / 100
+		response.ResponseRate = &rate
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) ListSellerCars(viewerId, id string, page, limit int) ([]ListCarResponse, []string) {
+	user, errors := i.getPublicUser(id)
+	if errors != nil {
+		return nil, errors
+	}
+
+	cars, err := i.services.CarRepo.GetActiveByOwner(user.ID, page, limit)
+	if err != nil {
+		log.Printf("Error fetching cars of user %s: %v\n", user.ID, err)
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	response, err := i.toListCarResponses(cars)
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	if err := i.markFavorites(viewerId, response); err != nil {
+		return nil, []string{"failed to fetch favorites"}
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) ExportAccount(userId string) (*AccountExportResponse, []string) {
+	profile, errors := i.GetProfile(userId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	cars, err := i.services.CarRepo.GetByOwner(userId)
+	if err != nil {
+		log.Printf("Error fetching cars of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	brands, err := i.GetBrands()
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	listings := []CarDetailResponse{}
+	for idx := range cars {
+		listings = append(listings, toCarDetailResponse(&cars[idx], user, nil, brands))
+	}
+
+	favorites := []FavoriteResponse{}
+	for page := 1; ; page++ {
+		batch, errors := i.ListFavorites(userId, page, accountExportPageSize)
+		if errors != nil {
+			return nil, errors
+		}
+		favorites = append(favorites, batch...)
+		if len(batch) < accountExportPageSize {
+			break
+		}
+	}
+
+	savedSearches, errors := i.ListSavedSearches(userId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	conversations, errors := i.exportConversations(userId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	offers := []OfferResponse{}
+	for page := 1; ; page++ {
+		batch, errors := i.ListOffers(userId, "", "", page, accountExportPageSize)
+		if errors != nil {
+			return nil, errors
+		}
+		offers = append(offers, batch...)
+		if len(batch) < accountExportPageSize {
+			break
+		}
+	}
+
+	notifications := []NotificationResponse{}
+	for page := 1; ; page++ {
+		batch, errors := i.ListNotifications(userId, false, page, accountExportPageSize)
+		if errors != nil {
+			return nil, errors
+		}
+		notifications = append(notifications, batch.Notifications...)
+		if len(batch.Notifications) < accountExportPageSize {
+			break
+		}
+	}
+
+	identities, err := i.services.UserIdentityRepo.GetByUser(userId)
+	if err != nil {
+		log.Printf("Error fetching identities of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch linked accounts"}
+	}
+
+	linkedAccounts := []LinkedAccountResponse{}
+	for _, identity := range identities {
+		linkedAccounts = append(linkedAccounts, LinkedAccountResponse{
+			Provider:  identity.Provider,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	changes, err := i.services.UserRepo.GetStatusChanges(userId)
+	if err != nil {
+		log.Printf("Error fetching status history of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch status history"}
+	}
+
+	history := []UserStatusChangeResponse{}
+	for _, change := range changes {
+		history = append(history, UserStatusChangeResponse{
+			OldStatus: change.OldStatus,
+			NewStatus: change.NewStatus,
+			Reason:    change.Reason,
+			CreatedAt: change.CreatedAt,
+		})
+	}
+
+	securityEvents := []SecurityEventResponse{}
+	for page := 1; ; page++ {
+		batch, errors := i.GetSecurityEvents(userId, page, accountExportPageSize)
+		if errors != nil {
+			return nil, errors
+		}
+		securityEvents = append(securityEvents, batch...)
+		if len(batch) < accountExportPageSize {
+			break
+		}
+	}
+
+	return &AccountExportResponse{
+		ExportedAt:     time.Now(),
+		SecurityEvents: securityEvents,
+		Profile:        *profile,
+		Listings:       listings,
+		Favorites:      favorites,
+		SavedSearches:  savedSearches,
+		Conversations:  conversations,
+		Offers:         offers,
+		Notifications:  notifications,
+		LinkedAccounts: linkedAccounts,
+		StatusHistory:  history,
+	}, nil
+}
+
+// exportConversations returns every conversation of the user with its full
+// message history, oldest message first.
+func (i *Interactor) exportConversations(userId string) ([]ConversationExportResponse, []string) {
+	response := []ConversationExportResponse{}
+	for page := 1; ; page++ {
+		conversations, errors := i.ListConversations(userId, page, accountExportPageSize)
+		if errors != nil {
+			return nil, errors
+		}
+
+		for _, conversation := range conversations {
+			messages := []MessageResponse{}
+			for messagePage := 1; ; messagePage++ {
+				batch, err := i.services.ConversationRepo.GetMessages(conversation.ID, messagePage, accountExportPageSize)
+				if err != nil {
+					log.Printf("Error fetching messages of conversation %s: %v\n", conversation.ID, err)
+					return nil, []string{"failed to fetch messages"}
+				}
+				for idx := range batch {
+					messages = append(messages, toMessageResponse(&batch[idx]))
+				}
+				if len(batch) < accountExportPageSize {
+					break
+				}
+			}
+			for left, right := 0, len(messages)-1; left < right; left, right = left+1, right-1 {
+				messages[left], messages[right] = messages[right], messages[left]
+			}
+
+			response = append(response, ConversationExportResponse{
+				ConversationResponse: conversation,
+				Messages:             messages,
+			})
+		}
+
+		if len(conversations) < accountExportPageSize {
+			break
+		}
+	}
+
+	return response, nil
+}
+
+// OpenMessageAttachment opens an attachment of a conversation the user takes
+// part in. It returns nil when the file no longer exists.
+func (i *Interactor) OpenMessageAttachment(userId, conversationId, fileName string) (io.ReadCloser, []string) {
+	conversation, errors := i.getConversation(userId, conversationId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	attachment, err := i.services.CDNRepo.OpenMessageAttachment(conversation.ID, fileName)
+	if err != nil {
+		log.Printf("Error opening attachment %s of conversation %s: %v\n", fileName, conversation.ID, err)
+		return nil, []string{"failed to read attachment"}
+	}
+	return attachment, nil
+}
+
+func (i *Interactor) GetUserAvatar(userId string) (io.ReadCloser, []string) {
+	avatar, err := i.services.CDNRepo.OpenUserAvatar(userId)
+	if err != nil {
+		log.Printf("Error opening avatar of user %s: %v\n", userId, err)
+		return nil, []string{"failed to read avatar"}
+	}
+	return avatar, nil
+}
+
+func (i *Interactor) RequestAccountDeletion(userId string, request DeleteAccountRequest) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !user.DeletionRequestedAt.IsZero() {
+		return []string{"Account deletion has already been requested."}
+	}
+
+	if request.Code != "" {
+		if errors := i.verifyDeletionCode(user.ID, request.Code); errors != nil {
+			return errors
+		}
+	} else if !comparePasswords(user.PasswordHash, request.Password) {
+		return []string{"Password is incorrect."}
+	}
+
+	memberships, err := i.services.OrganizationRepo.GetMemberships(user.ID)
+	if err != nil {
+		log.Printf("Error fetching memberships of user %s: %v\n", user.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	for _, membership := range memberships {
+		if membership.Role != OrganizationRoleOwner {
+			continue
+		}
+		if errors := i.ensureAnotherOwner(membership.OrganizationID); errors != nil {
+			return []string{"Please transfer ownership of your organizations before deleting your account."}
+		}
+	}
+
+	user.DeletionRequestedAt = time.Now()
+	err = i.services.UserRepo.ScheduleDeletion(user.ID, user.DeletionRequestedAt)
+	if err != nil {
+		log.Printf("Error scheduling account deletion: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.sendEmail(user, EmailTemplateAccountDeletion, map[string]interface{}{
+		"DeletionDate": *deletionScheduledAt(user),
+	})
+	if err != nil {
+		log.Printf("Error sending account deletion email: %v\n", err)
+	}
+
+	return nil
+}
+
+// SendAccountDeletionCode emails a one-time code that confirms an account
+// deletion in place of the password, for users who sign in through a social
+// provider and never set one.
+func (i *Interactor) SendAccountDeletionCode(userId string) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if !user.DeletionRequestedAt.IsZero() {
+		return []string{"Account deletion has already been requested."}
+	}
+
+	code, err := generateNumericCode(accountDeletionCodeLength)
+	if err != nil {
+		log.Printf("Error generating account deletion code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.AccountDeletionRepo.SaveDeletionCode(user.ID, hashCode(code), accountDeletionCodeTTL)
+	if err != nil {
+		log.Printf("Error saving account deletion code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.sendEmail(user, EmailTemplateAccountDeletionCode, map[string]interface{}{
+		"Code":    code,
+		"Minutes": int(accountDeletionCodeTTL.Minutes()),
+	})
+	if err != nil {
+		log.Printf("Error sending account deletion code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) verifyDeletionCode(userId, code string) []string {
+	attempts, err := i.services.AccountDeletionRepo.IncrementAttempts(userId, accountDeletionCodeTTL)
+	if err != nil {
+		log.Printf("Error counting account deletion code attempts: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if attempts > accountDeletionMaxAttempts {
+		err = i.services.AccountDeletionRepo.DeleteDeletionCode(userId)
+		if err != nil {
+			log.Printf("Error deleting account deletion code: %v\n", err)
+		}
+		return []string{"Invalid or expired confirmation code."}
+	}
+
+	valid, err := i.services.AccountDeletionRepo.VerifyDeletionCode(userId, hashCode(strings.TrimSpace(code)))
+	if err != nil {
+		log.Printf("Error verifying account deletion code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if !valid {
+		return []string{"Invalid or expired confirmation code."}
+	}
+
+	err = i.services.AccountDeletionRepo.DeleteDeletionCode(userId)
+	if err != nil {
+		log.Printf("Error deleting account deletion code: %v\n", err)
+	}
+
+	return nil
+}
+
+func (i *Interactor) CancelAccountDeletion(userId string) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if user.DeletionRequestedAt.IsZero() {
+		return []string{"Account deletion has not been requested."}
+	}
+
+	err = i.services.UserRepo.CancelDeletion(user.ID)
+	if err != nil {
+		log.Printf("Error cancelling account deletion: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) PurgeDeletedAccounts() {
+	users, err := i.services.UserRepo.GetDeletionsDue(time.Now().Add(-accountDeletionGracePeriod))
+	if err != nil {
+		log.Printf("Error fetching accounts due for deletion: %v\n", err)
+		return
+	}
+
+	for idx := range users {
+		user := &users[idx]
+
+		passwordHash, err := unusablePasswordHash()
+		if err != nil {
+			log.Printf("Error hashing password of user %s: %v\n", user.ID, err)
+			continue
+		}
+
+		user.FirstName = "Deleted"
+		user.LastName = "User"
+		user.ImageUrl = ""
+		user.CountryCode = ""
+		user.PhoneNumber = ""
+		user.Email = fmt.Sprintf("deleted-%s@carwise.invalid", user.ID)
+		user.PasswordHash = passwordHash
+		user.Status = AccountStatusDeleted
+		user.EmailVerified = false
+		user.PhoneVerified = false
+		user.TwoFactorEnabled = false
+		user.TwoFactorSecret = ""
+
+		conversationIDs, err := i.services.ConversationRepo.GetIDsByUser(user.ID)
+		if err != nil {
+			log.Printf("Error fetching conversations of user %s: %v\n", user.ID, err)
+			continue
+		}
+
+		err = i.services.UserRepo.Anonymize(user)
+		if err != nil {
+			log.Printf("Error anonymizing user %s: %v\n", user.ID, err)
+			continue
+		}
+
+		for _, conversationID := range conversationIDs {
+			err = i.services.CDNRepo.DeleteConversationAttachments(conversationID)
+			if err != nil {
+				log.Printf("Error deleting attachments of conversation %s: %v\n", conversationID, err)
+			}
+		}
+
+		err = i.services.CDNRepo.DeleteUserAvatar(user.ID)
+		if err != nil {
+			log.Printf("Error deleting avatar of user %s: %v\n", user.ID, err)
+		}
+
+		err = i.services.TokenRepo.RevokeUserTokens(user.ID, time.Now())
+		if err != nil {
+			log.Printf("Error revoking user tokens: %v\n", err)
+		}
+	}
+}
+
+func (i *Interactor) SendPhoneVerification(userId string) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if user.PhoneVerified {
+		return []string{"Phone number is already verified."}
+	}
+
+	count, err := i.services.PhoneVerificationRepo.IncrementSendCount(userId, phoneOTPSendWindow)
+	if err != nil {
+		log.Printf("Error counting phone verification codes: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if count > phoneOTPSendLimit {
+		return []string{"Too many verification codes requested. Please try again later."}
+	}
+
+	if user.PhoneNumber == "" {
+		return []string{"Please add a phone number to your profile first."}
+	}
+
+	countryCode, phoneNumber, err := normalizePhoneNumber(user.CountryCode, user.PhoneNumber)
+	if err != nil {
+		return []string{"Invalid phone number."}
+	}
+	phone := formatE164(countryCode, phoneNumber)
+
+	code, err := generateNumericCode(phoneOTPLength)
+	if err != nil {
+		log.Printf("Error generating phone verification code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.PhoneVerificationRepo.SaveOTP(userId, &PhoneOTP{
+		Phone:    phone,
+		CodeHash: hashCode(code),
+	}, phoneOTPTTL)
+	if err != nil {
+		log.Printf("Error saving phone verification code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	body := fmt.Sprintf("Your Carwise verification code is %s. It expires in %d minutes.", code, int(phoneOTPTTL.Minutes()))
+	err = i.services.SMSGW.Send(phone, body)
+	if err != nil {
+		log.Printf("Error sending phone verification code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) VerifyPhone(userId string, request PhoneVerifyRequest) []string {
+	otp, err := i.services.PhoneVerificationRepo.GetOTP(userId)
+	if err != nil {
+		log.Printf("Error fetching phone verification code: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if otp == nil {
+		return []string{"Invalid or expired verification code."}
+	}
+
+	attempts, err := i.services.PhoneVerificationRepo.IncrementOTPAttempts(userId)
+	if err != nil {
+		log.Printf("Error counting phone verification attempts: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if attempts > phoneOTPMaxAttempts {
+		err = i.services.PhoneVerificationRepo.DeleteOTP(userId)
+		if err != nil {
+			log.Printf("Error deleting phone verification code: %v\n", err)
+		}
+		return []string{"Too many failed attempts. Please request a new code."}
+	}
+
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(hashCode(request.Code))) != 1 {
+		return []string{"Invalid or expired verification code."}
+	}
+
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	countryCode, phoneNumber, err := normalizePhoneNumber(user.CountryCode, user.PhoneNumber)
+	if err != nil || formatE164(countryCode, phoneNumber) != otp.Phone {
+		return []string{"Phone number has changed. Please request a new code."}
+	}
+
+	user.PhoneVerified = true
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		log.Printf("Error marking phone as verified: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	err = i.services.PhoneVerificationRepo.DeleteOTP(userId)
+	if err != nil {
+		log.Printf("Error deleting phone verification code: %v\n", err)
+	}
+
+	return nil
+}
+
+func (i *Interactor) EditProfile(userId string, request ProfileEditRequest, avatar *multipart.FileHeader) []string {
+	var errors []string
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to get user: %v", err))
+		return errors
+	}
+
+	countryCode, phoneNumber, err := normalizePhoneNumber(request.CountryCode, request.PhoneNumber)
+	if err != nil {
+		errors = append(errors, "Invalid phone number.")
+		return errors
+	}
+
+	if user.CountryCode != countryCode || user.PhoneNumber != phoneNumber {
+		user.PhoneVerified = false
+	}
+
+	user.FirstName = request.FirstName
+	user.LastName = request.LastName
+	user.CountryCode = countryCode
+	user.PhoneNumber = phoneNumber
+	if request.Locale != "" {
+		user.Locale = request.Locale
+	}
+
+	err = i.services.UserRepo.Update(user)
+	if err != nil {
+		errors = append(errors, fmt.Sprintf("Failed to update user profile: %v", err))
+		return errors
+	}
+
+	if avatar != nil {
+		file, err := avatar.Open()
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to open avatar file: %v", err))
+			return errors
+		}
+		defer file.Close()
+
+		avatarURL, err := i.services.CDNRepo.SaveUserAvatar(userId, file)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to upload avatar: %v", err))
+			return errors
+		}
+
+		user.ImageUrl = avatarURL
+		err = i.services.UserRepo.Update(user)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("Failed to update user avatar URL: %v", err))
+			return errors
+		}
+	}
+
+	return nil
+}
+
+func (i *Interactor) CreateOrganization(userId string, request OrganizationRequest, logo *multipart.FileHeader) (*OrganizationResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if !user.EmailVerified {
+		return nil, []string{"Please verify your email address before creating an organization."}
+	}
+
+	countryCode, phoneNumber, err := normalizePhoneNumber(request.CountryCode, request.PhoneNumber)
+	if err != nil {
+		return nil, []string{"Invalid phone number."}
+	}
+
+	organization := &Organization{
+		ID:          uuid.New().String(),
+		Name:        request.Name,
+		TaxNumber:   request.TaxNumber,
+		TaxOffice:   request.TaxOffice,
+		Address:     request.Address,
+		City:        request.City,
+		CountryCode: countryCode,
+		PhoneNumber: phoneNumber,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	err = i.services.OrganizationRepo.Create(organization, &OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         userId,
+		Role:           OrganizationRoleOwner,
+		CreatedAt:      time.Now(),
+	})
+	if err != nil {
+		return nil, []string{"Failed to create organization: " + err.Error()}
+	}
+
+	if errors := i.saveOrganizationLogo(organization, logo); errors != nil {
+		return nil, errors
+	}
+
+	return i.toOrganizationResponse(organization, true)
+}
+
+func (i *Interactor) UpdateOrganization(userId, organizationId string, request OrganizationRequest, logo *multipart.FileHeader) []string {
+	organization, member, errors := i.getOrganizationMember(organizationId, userId)
+	if errors != nil {
+		return errors
+	}
+	if !canManageOrganization(member) {
+		return []string{"Only owners and managers can edit the organization."}
+	}
+
+	countryCode, phoneNumber, err := normalizePhoneNumber(request.CountryCode, request.PhoneNumber)
+	if err != nil {
+		return []string{"Invalid phone number."}
+	}
+
+	wasVerified := organization.Verified
+	if organization.TaxNumber != request.TaxNumber || organization.TaxOffice != request.TaxOffice || organization.Name != request.Name {
+		organization.Verified = false
+		organization.VerifiedAt = time.Time{}
+	}
+
+	organization.Name = request.Name
+	organization.TaxNumber = request.TaxNumber
+	organization.TaxOffice = request.TaxOffice
+	organization.Address = request.Address
+	organization.City = request.City
+	organization.CountryCode = countryCode
+	organization.PhoneNumber = phoneNumber
+
+	err = i.services.OrganizationRepo.Update(organization)
+	if err != nil {
+		return []string{"Failed to update organization: " + err.Error()}
+	}
+
+	if wasVerified && !organization.Verified {
+		if errors := i.updateOrganizationSellerType(organization.ID, false); errors != nil {
+			return errors
+		}
+	}
+
+	return i.saveOrganizationLogo(organization, logo)
+}
+
+func (i *Interactor) saveOrganizationLogo(organization *Organization, logo *multipart.FileHeader) []string {
+	if logo == nil {
+		return nil
+	}
+
+	file, err := logo.Open()
+	if err != nil {
+		return []string{fmt.Sprintf("Failed to open logo file: %v", err)}
+	}
+	defer file.Close()
+
+	logoURL, err := i.services.CDNRepo.SaveOrganizationLogo(organization.ID, file)
+	if err != nil {
+		return []string{fmt.Sprintf("Failed to upload logo: %v", err)}
+	}
+
+	organization.LogoUrl = logoURL
+	err = i.services.OrganizationRepo.Update(organization)
+	if err != nil {
+		return []string{fmt.Sprintf("Failed to update organization logo URL: %v", err)}
+	}
+
+	return nil
+}
+
+func (i *Interactor) GetOrganization(organizationId string) (*OrganizationResponse, []string) {
+	organization, errors := i.getOrganization(organizationId)
+	if errors != nil {
+		return nil, errors
+	}
+	return i.toOrganizationResponse(organization, false)
+}
+
+func (i *Interactor) ListOrganizationCars(viewerId, organizationId string, page, limit int) ([]ListCarResponse, []string) {
+	organization, errors := i.getOrganization(organizationId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	cars, err := i.services.CarRepo.GetActiveByOrganization(organization.ID, page, limit)
+	if err != nil {
+		log.Printf("Error fetching cars of organization %s: %v\n", organization.ID, err)
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	response, err := i.toListCarResponses(cars)
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	if err := i.markFavorites(viewerId, response); err != nil {
+		return nil, []string{"failed to fetch favorites"}
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) GetMyOrganizations(userId string) ([]MembershipResponse, []string) {
+	memberships, err := i.services.OrganizationRepo.GetMemberships(userId)
+	if err != nil {
+		log.Printf("Error fetching memberships of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch organizations"}
+	}
+
+	response := []MembershipResponse{}
+	for _, membership := range memberships {
+		organization, err := i.services.OrganizationRepo.GetByID(membership.OrganizationID)
+		if err != nil {
+			log.Printf("Error fetching organization %s: %v\n", membership.OrganizationID, err)
+			return nil, []string{"failed to fetch organizations"}
+		}
+
+		organizationResponse, errors := i.toOrganizationResponse(organization, true)
+		if errors != nil {
+			return nil, errors
+		}
+
+		response = append(response, MembershipResponse{
+			Organization: *organizationResponse,
+			Role:         membership.Role,
+		})
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) GetOrganizationMembers(userId, organizationId string) ([]OrganizationMemberResponse, []string) {
+	_, _, errors := i.getOrganizationMember(organizationId, userId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	members, err := i.services.OrganizationRepo.GetMembers(organizationId)
+	if err != nil {
+		log.Printf("Error fetching members of organization %s: %v\n", organizationId, err)
+		return nil, []string{"failed to fetch members"}
+	}
+
+	response := []OrganizationMemberResponse{}
+	for _, member := range members {
+		user, err := i.services.UserRepo.GetByID(member.UserID)
+		if err != nil {
+			log.Printf("Error fetching user %s: %v\n", member.UserID, err)
+			return nil, []string{"failed to fetch members"}
+		}
+
+		response = append(response, OrganizationMemberResponse{
+			UserID:    user.ID,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Email:     user.Email,
+			Role:      member.Role,
+			CreatedAt: member.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+// InviteOrganizationMember answers the same way whether or not an account
+// exists for the address, so it cannot be used to probe for users.
+func (i *Interactor) InviteOrganizationMember(userId, organizationId string, request OrganizationMemberRequest) (*OrganizationInvitationResponse, []string) {
+	organization, actor, errs := i.getOrganizationMember(organizationId, userId)
+	if errs != nil {
+		return nil, errs
+	}
+	if !canManageOrganization(actor) {
+		return nil, []string{"Only owners and managers can invite members."}
+	}
+	if request.Role == OrganizationRoleOwner && actor.Role != OrganizationRoleOwner {
+		return nil, []string{"Only owners can invite other owners."}
+	}
+
+	now := time.Now()
+	invitation := &OrganizationInvitation{
+		ID:             uuid.New().String(),
+		OrganizationID: organization.ID,
+		Email:          strings.ToLower(strings.TrimSpace(request.Email)),
+		Role:           request.Role,
+		InvitedBy:      userId,
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(organizationInvitationTTL),
+	}
+	err := i.services.OrganizationRepo.SaveInvitation(invitation)
+	if err != nil {
+		log.Printf("Error saving organization invitation: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	go i.sendOrganizationInvitation(organization, *invitation)
+
+	response := toOrganizationInvitationResponse(invitation, nil)
+	return &response, nil
+}
+
+func (i *Interactor) sendOrganizationInvitation(organization *Organization, invitation OrganizationInvitation) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error sending organization invitation: %v\n", r)
+		}
+	}()
+
+	user, err := i.services.UserRepo.GetByEmail(invitation.Email)
+	if errors.Is(err, ErrUserNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error fetching user by email: %v\n", err)
+		return
+	}
+
+	member, err := i.services.OrganizationRepo.GetMember(organization.ID, user.ID)
+	if err != nil {
+		log.Printf("Error fetching organization member: %v\n", err)
+		return
+	}
+	if member != nil {
+		return
+	}
+
+	inviter, err := i.services.UserRepo.GetByID(invitation.InvitedBy)
+	if err != nil {
+		log.Printf("Error fetching user %s: %v\n", invitation.InvitedBy, err)
+		return
+	}
+
+	err = i.sendEmail(user, EmailTemplateOrganizationInvitation, map[string]interface{}{
+		"Inviter":      inviter.FirstName + " " + inviter.LastName,
+		"Organization": organization.Name,
+		"Role":         invitation.Role,
+		"Days":         int(organizationInvitationTTL.Hours() / 24),
+		"Link":         i.config.FrontendURL + "/profile/invitations",
+	})
+	if err != nil {
+		log.Printf("Error sending organization invitation: %v\n", err)
+	}
+}
+
+func (i *Interactor) GetOrganizationInvitations(userId, organizationId string) ([]OrganizationInvitationResponse, []string) {
+	_, actor, errors := i.getOrganizationMember(organizationId, userId)
+	if errors != nil {
+		return nil, errors
+	}
+	if !canManageOrganization(actor) {
+		return nil, []string{"Only owners and managers can view invitations."}
+	}
+
+	invitations, err := i.services.OrganizationRepo.GetInvitations(organizationId, time.Now())
+	if err != nil {
+		log.Printf("Error fetching invitations of organization %s: %v\n", organizationId, err)
+		return nil, []string{"failed to fetch invitations"}
+	}
+
+	response := []OrganizationInvitationResponse{}
+	for idx := range invitations {
+		response = append(response, toOrganizationInvitationResponse(&invitations[idx], nil))
+	}
+	return response, nil
+}
+
+func (i *Interactor) CancelOrganizationInvitation(userId, organizationId, invitationId string) []string {
+	_, actor, errors := i.getOrganizationMember(organizationId, userId)
+	if errors != nil {
+		return errors
+	}
+	if !canManageOrganization(actor) {
+		return []string{"Only owners and managers can cancel invitations."}
+	}
+
+	invitation, err := i.services.OrganizationRepo.GetInvitation(invitationId)
+	if err != nil {
+		log.Printf("Error fetching organization invitation: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if invitation == nil || invitation.OrganizationID != organizationId {
+		return []string{"invitation not found"}
+	}
+	if invitation.Role == OrganizationRoleOwner && actor.Role != OrganizationRoleOwner {
+		return []string{"Only owners can cancel owner invitations."}
+	}
+
+	err = i.services.OrganizationRepo.DeleteInvitation(invitation.ID)
+	if err != nil {
+		log.Printf("Error deleting organization invitation %s: %v\n", invitation.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	return nil
+}
+
+func (i *Interactor) GetMyInvitations(userId string) ([]OrganizationInvitationResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{"user not found"}
+	}
+
+	response := []OrganizationInvitationResponse{}
+	if !user.EmailVerified {
+		return response, nil
+	}
+
+	invitations, err := i.services.OrganizationRepo.GetInvitationsByEmail(user.Email, time.Now())
+	if err != nil {
+		log.Printf("Error fetching invitations of user %s: %v\n", user.ID, err)
+		return nil, []string{"failed to fetch invitations"}
+	}
+
+	for idx := range invitations {
+		organization, err := i.services.OrganizationRepo.GetByID(invitations[idx].OrganizationID)
+		if err != nil || organization == nil {
+			log.Printf("Error fetching organization %s: %v\n", invitations[idx].OrganizationID, err)
+			continue
+		}
+		summary := toOrganizationSummaryResponse(organization)
+		response = append(response, toOrganizationInvitationResponse(&invitations[idx], &summary))
+	}
+	return response, nil
+}
+
+func (i *Interactor) AcceptOrganizationInvitation(userId, invitationId string) []string {
+	user, invitation, errs := i.getOwnInvitation(userId, invitationId)
+	if errs != nil {
+		return errs
+	}
+
+	err := i.services.OrganizationRepo.AcceptInvitation(invitation.ID, user.ID, time.Now())
+	if err != nil {
+		if errors.Is(err, ErrInvitationNotFound) {
+			return []string{"invitation not found"}
+		}
+		log.Printf("Error accepting organization invitation %s: %v\n", invitation.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	return nil
+}
+
+func (i *Interactor) DeclineOrganizationInvitation(userId, invitationId string) []string {
+	_, invitation, errs := i.getOwnInvitation(userId, invitationId)
+	if errs != nil {
+		return errs
+	}
+
+	err := i.services.OrganizationRepo.DeleteInvitation(invitation.ID)
+	if err != nil {
+		log.Printf("Error deleting organization invitation %s: %v\n", invitation.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	return nil
+}
+
+// getOwnInvitation only hands out invitations addressed to the verified email
+// address of the user.
+func (i *Interactor) getOwnInvitation(userId, invitationId string) (*User, *OrganizationInvitation, []string) {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, nil, []string{"user not found"}
+	}
+	if !user.EmailVerified {
+		return nil, nil, []string{"Please verify your email address first."}
+	}
+
+	invitation, err := i.services.OrganizationRepo.GetInvitation(invitationId)
+	if err != nil {
+		log.Printf("Error fetching organization invitation: %v\n", err)
+		return nil, nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if invitation == nil || !strings.EqualFold(invitation.Email, user.Email) || !invitation.ExpiresAt.After(time.Now()) {
+		return nil, nil, []string{"invitation not found"}
+	}
+	return user, invitation, nil
+}
+
+func (i *Interactor) ChangeOrganizationMemberRole(userId, organizationId, memberId string, request OrganizationMemberRoleRequest) []string {
+	_, actor, errs := i.getOrganizationMember(organizationId, userId)
+	if errs != nil {
+		return errs
+	}
+	if actor.Role != OrganizationRoleOwner {
+		return []string{"Only owners can change member roles."}
+	}
+
+	if _, errs := i.getOtherMember(organizationId, memberId); errs != nil {
+		return errs
+	}
+
+	err := i.services.OrganizationRepo.UpdateMemberRole(organizationId, memberId, request.Role)
+	if err != nil {
+		if errors.Is(err, ErrLastOrganizationOwner) {
+			return []string{"An organization must have at least one owner."}
+		}
+		return []string{"Failed to change member role: " + err.Error()}
+	}
+
+	return nil
+}
+
+func (i *Interactor) RemoveOrganizationMember(userId, organizationId, memberId string) []string {
+	_, actor, errs := i.getOrganizationMember(organizationId, userId)
+	if errs != nil {
+		return errs
+	}
+
+	member, errs := i.getOtherMember(organizationId, memberId)
+	if errs != nil {
+		return errs
+	}
+
+	switch {
+	case member.UserID == actor.UserID:
+	case actor.Role == OrganizationRoleOwner:
+	case actor.Role == OrganizationRoleManager && member.Role == OrganizationRoleSalesperson:
+	default:
+		return []string{"You are not allowed to remove this member."}
+	}
+
+	err := i.services.OrganizationRepo.RemoveMember(organizationId, memberId)
+	if err != nil {
+		if errors.Is(err, ErrLastOrganizationOwner) {
+			return []string{"An organization must have at least one owner."}
+		}
+		return []string{"Failed to remove member: " + err.Error()}
+	}
+
+	return nil
+}
+
+func (i *Interactor) SearchOrganizations(query, verification string, page, limit int) ([]OrganizationResponse, []string) {
+	organizations, err := i.services.OrganizationRepo.Search(query, verification, page, limit)
+	if err != nil {
+		log.Printf("Error searching organizations: %v\n", err)
+		return nil, []string{"failed to search organizations"}
+	}
+
+	response := []OrganizationResponse{}
+	for idx := range organizations {
+		organizationResponse, errors := i.toOrganizationResponse(&organizations[idx], true)
+		if errors != nil {
+			return nil, errors
+		}
+		response = append(response, *organizationResponse)
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) VerifyOrganization(organizationId string, request OrganizationVerifyRequest) []string {
+	organization, errors := i.getOrganization(organizationId)
+	if errors != nil {
+		return errors
+	}
+
+	err := i.services.OrganizationRepo.SetVerified(organization.ID, *request.Verified, time.Now())
+	if err != nil {
+		log.Printf("Error verifying organization %s: %v\n", organization.ID, err)
+		return []string{"failed to verify organization"}
+	}
+
+	return i.updateOrganizationSellerType(organization.ID, *request.Verified)
+}
+
+// updateOrganizationSellerType keeps the listings of an organization marked
+// as dealer listings only while the organization is verified.
+func (i *Interactor) updateOrganizationSellerType(organizationId string, verified bool) []string {
+	sellerType := SellerTypeIndividual
+	if verified {
+		sellerType = SellerTypeDealer
+	}
+
+	err := i.services.CarRepo.SetOrganizationSellerType(organizationId, sellerType)
+	if err != nil {
+		log.Printf("Error updating seller type of organization %s: %v\n", organizationId, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	return nil
+}
+
+func (i *Interactor) getOrganization(organizationId string) (*Organization, []string) {
+	organization, err := i.services.OrganizationRepo.GetByID(organizationId)
+	if err != nil {
+		log.Printf("Error fetching organization %s: %v\n", organizationId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if organization == nil {
+		return nil, []string{"organization not found"}
+	}
+	return organization, nil
+}
+
+func (i *Interactor) getOrganizationMember(organizationId, userId string) (*Organization, *OrganizationMember, []string) {
+	organization, errors := i.getOrganization(organizationId)
+	if errors != nil {
+		return nil, nil, errors
+	}
+
+	member, err := i.services.OrganizationRepo.GetMember(organizationId, userId)
+	if err != nil {
+		log.Printf("Error fetching organization member: %v\n", err)
+		return nil, nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if member == nil {
+		return nil, nil, []string{"You are not a member of this organization."}
+	}
+
+	return organization, member, nil
+}
+
+func (i *Interactor) getOtherMember(organizationId, memberId string) (*OrganizationMember, []string) {
+	member, err := i.services.OrganizationRepo.GetMember(organizationId, memberId)
+	if err != nil {
+		log.Printf("Error fetching organization member: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if member == nil {
+		return nil, []string{"member not found"}
+	}
+	return member, nil
+}
+
+func (i *Interactor) ensureAnotherOwner(organizationId string) []string {
+	members, err := i.services.OrganizationRepo.GetMembers(organizationId)
+	if err != nil {
+		log.Printf("Error fetching members of organization %s: %v\n", organizationId, err)
+		return []string{"failed to fetch members"}
+	}
+
+	owners := 0
+	for _, member := range members {
+		if member.Role == OrganizationRoleOwner {
+			owners++
+		}
+	}
+	if owners < 2 {
+		return []string{"An organization must have at least one owner."}
+	}
+	return nil
+}
+
+func canManageOrganization(member *OrganizationMember) bool {
+	return member.Role == OrganizationRoleOwner || member.Role == OrganizationRoleManager
+}
+
+func (i *Interactor) toOrganizationResponse(organization *Organization, private bool) (*OrganizationResponse, []string) {
+	activeListings, err := i.services.OrganizationRepo.CountActiveListings(organization.ID)
+	if err != nil {
+		log.Printf("Error counting listings of organization %s: %v\n", organization.ID, err)
+		return nil, []string{"failed to fetch organization"}
+	}
+
+	response := &OrganizationResponse{
+		OrganizationSummaryResponse: toOrganizationSummaryResponse(organization),
+		Address:                     organization.Address,
+		CountryCode:                 organization.CountryCode,
+		PhoneNumber:                 organization.PhoneNumber,
+		ActiveListings:              activeListings,
+		CreatedAt:                   organization.CreatedAt,
+	}
+	if organization.Verified {
+		verifiedAt := organization.VerifiedAt
+		response.VerifiedAt = &verifiedAt
+	}
+	if private {
+		response.TaxNumber = organization.TaxNumber
+		response.TaxOffice = organization.TaxOffice
+	}
+
+	return response, nil
+}
+
+func toOrganizationInvitationResponse(invitation *OrganizationInvitation, organization *OrganizationSummaryResponse) OrganizationInvitationResponse {
+	return OrganizationInvitationResponse{
+		ID:           invitation.ID,
+		Organization: organization,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		CreatedAt:    invitation.CreatedAt,
+		ExpiresAt:    invitation.ExpiresAt,
+	}
+}
+
+func toOrganizationSummaryResponse(organization *Organization) OrganizationSummaryResponse {
+	return OrganizationSummaryResponse{
+		ID:       organization.ID,
+		Name:     organization.Name,
+		LogoUrl:  organization.LogoUrl,
+		City:     organization.City,
+		Verified: organization.Verified,
+	}
+}
+
+func (i *Interactor) CreateCar(userId string, request CarCreateRequest) []string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if !user.EmailVerified {
+		return []string{"Please verify your email address before creating a listing."}
+	}
+
+	request.OwnerId = userId
+	request.ID = uuid.New().String()
+	request.ListingDate = time.Now()
+	request.ListingNumber, err = generateSecureListingNumber(10)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	//for _, v := range request.Images {
+	//	// v byte -> image
+	//}
+
+	car := request.ToCar()
+	car.ExpiresAt = request.ListingDate.Add(listingLifetime)
+	if car.OrganizationID != "" {
+		organization, _, errors := i.getOrganizationMember(car.OrganizationID, userId)
+		if errors != nil {
+			return errors
+		}
+		if !organization.Verified {
+			return []string{"Your organization must be verified before posting listings."}
+		}
+		car.SellerType = SellerTypeDealer
+	}
+	err = i.services.CarRepo.Create(car)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	return nil
+}
+
+func (i *Interactor) UpdateCar(userId, carId string, request CarCreateRequest) []string {
+	existing, err := i.services.CarRepo.GetByID(carId)
+	if err != nil {
+		return []string{"car not found"}
+	}
+
+	if !i.canManageCar(existing, userId) {
+		return []string{"car not found"}
+	}
+
+	if existing.Status == CarStatusSold {
+		return []string{"Sold listings can no longer be edited."}
+	}
+
+	request.ID = existing.ID
+	request.OwnerId = existing.OwnerId
+	request.OrganizationID = existing.OrganizationID
+	car := request.ToCar()
+	car.SellerType = existing.SellerType
+	car.StockNumber = existing.StockNumber
+
+	err = i.saveListing(existing, car)
+	if err != nil {
+		log.Printf("Error updating car %s: %v\n", car.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+// canManageCar reports whether the user may edit the listing. Organization
+// listings are managed through the current membership only, so a salesperson
+// who leaves the organization loses access to the listings they posted.
+func (i *Interactor) canManageCar(car *Car, userId string) bool {
+	if car.OrganizationID == "" {
+		return car.OwnerId == userId
+	}
+
+	_, member, errors := i.getOrganizationMember(car.OrganizationID, userId)
+	if errors != nil {
+		return false
+	}
+	return car.OwnerId == userId || canManageOrganization(member)
+}
+
+func (i *Interactor) saveListing(previous, car *Car) error {
+	err := i.services.CarRepo.UpdateListing(car)
+	if err != nil {
+		return err
+	}
+
+	if previous.Price == car.Price && previous.Currency == car.Currency {
+		return nil
+	}
+
+	err = i.services.PriceHistoryRepo.Create(&PriceChange{
+		CarID:       car.ID,
+		OldPrice:    previous.Price,
+		OldCurrency: previous.Currency,
+		NewPrice:    car.Price,
+		NewCurrency: car.Currency,
+		ChangedAt:   time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error recording price change of car %s: %v\n", car.ID, err)
+	}
+
+	if previous.Currency == car.Currency && car.Price < previous.Price && previous.Status == CarStatusActive {
+		go i.notifyPriceDrop(car, previous.Price)
+	}
+
+	return nil
+}
+
+func (i *Interactor) notifyPriceDrop(car *Car, oldPrice float64) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error notifying watchers of car %s: %v\n", car.ID, r)
+		}
+	}()
+
+	userIDs, err := i.services.FavoriteRepo.GetUserIDsByCar(car.ID)
+	if err != nil {
+		log.Printf("Error fetching watchers of car %s: %v\n", car.ID, err)
+		return
+	}
+
+	event := PriceDropEventResponse{
+		CarID:    car.ID,
+		Title:    car.Title,
+		OldPrice: oldPrice,
+		NewPrice: car.Price,
+		Currency: car.Currency,
+	}
+
+	for _, userID := range userIDs {
+		if userID == car.OwnerId {
+			continue
+		}
+
+		locale := i.userLocale(userID)
+		notification := &Notification{
+			UserID: userID,
+			Type:   NotificationTypePriceDrop,
+			Title:  i.services.EmailRenderer.Translate(locale, "notification.price_drop.title"),
+			Body: i.services.EmailRenderer.Translate(locale, "notification.price_drop.body", car.Title,
+				fmt.Sprintf("%.2f %s", car.Price, car.Currency), fmt.Sprintf("%.2f %s", oldPrice, car.Currency)),
+			Link: "/cars/" + car.ID,
+			Data: event,
+		}
+		if !i.notify(EventTypePriceDrop, notification) {
+			continue
+		}
+
+		user, err := i.services.UserRepo.GetByID(userID)
+		if err != nil {
+			log.Printf("Error fetching user %s: %v\n", userID, err)
+			continue
+		}
+		if user.Status != AccountStatusActive || !user.EmailVerified {
+			continue
+		}
+
+		err = i.sendEmail(user, EmailTemplatePriceDrop, map[string]interface{}{
+			"Title":    car.Title,
+			"OldPrice": fmt.Sprintf("%.2f %s", oldPrice, car.Currency),
+			"NewPrice": fmt.Sprintf("%.2f %s", car.Price, car.Currency),
+			"Link":     fmt.Sprintf("%s/cars/%s", i.config.FrontendURL, car.ID),
+		})
+		if err != nil {
+			log.Printf("Error sending price drop email: %v\n", err)
+		}
+	}
+}
+
+func (i *Interactor) RevealContact(viewerId, carId, ip string) (*ContactRevealResponse, []string) {
+	viewer, err := i.services.UserRepo.GetByID(viewerId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if !viewer.EmailVerified {
+		return nil, []string{"Please verify your email address before contacting sellers."}
+	}
+
+	car, err := i.services.CarRepo.GetByID(carId)
+	if err != nil || car.Status != CarStatusActive {
+		return nil, []string{"car not found"}
+	}
+
+	owner, errors := i.getPublicUser(car.OwnerId)
+	if errors != nil {
+		return nil, []string{"car not found"}
+	}
+
+	countryCode, phoneNumber, showPhoneNumber := owner.CountryCode, owner.PhoneNumber, owner.ShowPhoneNumber
+	if car.OrganizationID != "" {
+		// Organization listings are answered on the organization's line,
+		// never on the salesperson's personal phone.
+		organization, errors := i.getOrganization(car.OrganizationID)
+		if errors != nil {
+			return nil, errors
+		}
+		countryCode, phoneNumber, showPhoneNumber = organization.CountryCode, organization.PhoneNumber, true
+	}
+
+	if phoneNumber == "" {
+		return nil, []string{"The seller has not provided a phone number."}
+	}
+
+	// A relay number still rings the seller, so it is only handed out to
+	// sellers who chose to be reachable by phone.
+	if !showPhoneNumber && owner.ID != viewer.ID {
+		return nil, []string{"The seller prefers to be contacted through messages."}
+	}
+
+	masked := i.services.PhoneRelayGW != nil && owner.ID != viewer.ID
+
+	response := &ContactRevealResponse{
+		PhoneNumber: formatE164(countryCode, phoneNumber),
+	}
+	if masked {
+		response.PhoneNumber, response.Extension, err = i.services.PhoneRelayGW.Allocate(response.PhoneNumber, car.ID, viewer.ID, phoneRelayTTL)
+		if err != nil {
+			log.Printf("Error allocating relay number: %v\n", err)
+			return nil, []string{"An unexpected error occurred. Please try again later."}
+		}
+		expiresAt := time.Now().Add(phoneRelayTTL)
+		response.Masked = true
+		response.ExpiresAt = &expiresAt
+	}
+
+	if owner.ID != viewer.ID {
+		err = i.services.ContactRevealRepo.Create(&ContactReveal{
+			ID:        uuid.New().String(),
+			CarID:     car.ID,
+			SellerID:  owner.ID,
+			ViewerID:  viewer.ID,
+			IP:        ip,
+			Masked:    masked,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			log.Printf("Error recording contact reveal: %v\n", err)
+		}
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) ListMyCars(userId, status string) ([]MyListingResponse, []string) {
+	cars, err := i.services.CarRepo.GetManagedBy(userId)
+	if err != nil {
+		log.Printf("Error fetching cars of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	if status != "" {
+		filtered := []Car{}
+		for _, car := range cars {
+			if car.Status == status {
+				filtered = append(filtered, car)
+			}
+		}
+		cars = filtered
+	}
+
+	listings, err := i.toListCarResponses(cars)
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	carIDs := []string{}
+	for _, car := range cars {
+		carIDs = append(carIDs, car.ID)
+	}
+
+	reveals, err := i.services.ContactRevealRepo.CountByCars(carIDs)
+	if err != nil {
+		log.Printf("Error counting contact reveals of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch listing statistics"}
+	}
+
+	favorites, err := i.services.FavoriteRepo.CountByCars(carIDs)
+	if err != nil {
+		log.Printf("Error counting favorites of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch listing statistics"}
+	}
+
+	messages, err := i.services.ConversationRepo.CountByCars(carIDs)
+	if err != nil {
+		log.Printf("Error counting messages of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch listing statistics"}
+	}
+
+	response := []MyListingResponse{}
+	for idx, car := range cars {
+		response = append(response, MyListingResponse{
+			ListCarResponse:    listings[idx],
+			Status:             car.Status,
+			ExpiresAt:          car.ExpiresAt,
+			ViewCount:          car.ViewCount,
+			ContactRevealCount: reveals[car.ID],
+			FavoriteCount:      favorites[car.ID],
+			MessageCount:       messages[car.ID],
+		})
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) BulkUpdateListings(userId string, request BulkListingActionRequest) (*BulkListingActionResponse, []string) {
+	var fromStatuses []string
+	var status string
+	var expiresAt time.Time
+
+	switch request.Action {
+	case ListingActionPause:
+		fromStatuses = []string{CarStatusActive}
+		status = CarStatusPaused
+	case ListingActionRenew:
+		fromStatuses = []string{CarStatusActive, CarStatusPaused, CarStatusExpired}
+		status = CarStatusActive
+		expiresAt = time.Now().Add(listingLifetime)
+	case ListingActionMarkSold:
+		fromStatuses = []string{CarStatusActive, CarStatusPaused, CarStatusExpired}
+		status = CarStatusSold
+	default:
+		return nil, []string{"Unsupported listing action."}
+	}
+
+	updated, err := i.services.CarRepo.UpdateStatus(userId, request.CarIDs, fromStatuses, status, expiresAt)
+	if err != nil {
+		log.Printf("Error updating listings of user %s: %v\n", userId, err)
+		return nil, []string{"failed to update listings"}
+	}
+
+	updatedSet := make(map[string]bool)
+	for _, id := range updated {
+		updatedSet[id] = true
+	}
+
+	response := &BulkListingActionResponse{Updated: []string{}, Skipped: []string{}}
+	for _, id := range request.CarIDs {
+		if updatedSet[id] {
+			response.Updated = append(response.Updated, id)
+		} else {
+			response.Skipped = append(response.Skipped, id)
+		}
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) StartListingImport(userId, organizationId, format string, fullSync bool, feed []byte) (*ImportJobResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if !user.EmailVerified {
+		return nil, []string{"Please verify your email address before creating a listing."}
+	}
+
+	organization, member, errs := i.getOrganizationMember(organizationId, userId)
+	if errs != nil {
+		return nil, errs
+	}
+	if !canManageOrganization(member) {
+		return nil, []string{"Only owners and managers can import listings."}
+	}
+	if !organization.Verified {
+		return nil, []string{"Your organization must be verified before posting listings."}
+	}
+
+	parser, ok := i.services.FeedParsers[format]
+	if !ok {
+		return nil, []string{"Unsupported feed format."}
+	}
+
+	job := &ImportJob{
+		ID:             uuid.New().String(),
+		OrganizationID: organization.ID,
+		UserID:         userId,
+		Format:         format,
+		FullSync:       fullSync,
+		Status:         ImportJobStatusQueued,
+		CreatedAt:      time.Now(),
+	}
+	err = i.services.ImportJobRepo.Create(job)
+	if err != nil {
+		if errors.Is(err, ErrImportRunning) {
+			return nil, []string{"Another import is still running for this organization."}
+		}
+		log.Printf("Error creating import job: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	go i.runListingImport(job, parser, feed)
+
+	response := toImportJobResponse(job, nil)
+	return &response, nil
+}
+
+func (i *Interactor) GetListingImport(userId, organizationId, jobId string) (*ImportJobResponse, []string) {
+	_, member, errors := i.getOrganizationMember(organizationId, userId)
+	if errors != nil {
+		return nil, errors
+	}
+	if !canManageOrganization(member) {
+		return nil, []string{"Only owners and managers can view imports."}
+	}
+
+	job, err := i.services.ImportJobRepo.GetByID(jobId)
+	if err != nil {
+		log.Printf("Error fetching import job %s: %v\n", jobId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if job == nil || job.OrganizationID != organizationId {
+		return nil, []string{"import not found"}
+	}
+
+	rows, err := i.services.ImportJobRepo.GetRows(job.ID)
+	if err != nil {
+		log.Printf("Error fetching rows of import job %s: %v\n", job.ID, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	response := toImportJobResponse(job, rows)
+	return &response, nil
+}
+
+func (i *Interactor) ListListingImports(userId, organizationId string, page, limit int) ([]ImportJobResponse, []string) {
+	_, member, errors := i.getOrganizationMember(organizationId, userId)
+	if errors != nil {
+		return nil, errors
+	}
+	if !canManageOrganization(member) {
+		return nil, []string{"Only owners and managers can view imports."}
+	}
+
+	jobs, err := i.services.ImportJobRepo.GetByOrganization(organizationId, page, limit)
+	if err != nil {
+		log.Printf("Error fetching import jobs of organization %s: %v\n", organizationId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	response := []ImportJobResponse{}
+	for idx := range jobs {
+		response = append(response, toImportJobResponse(&jobs[idx], nil))
+	}
+	return response, nil
+}
+
+func (i *Interactor) FailStaleImports() {
+	failed, err := i.services.ImportJobRepo.FailStale(time.Now().Add(-importJobTimeout), "Import was interrupted before it finished.")
+	if err != nil {
+		log.Printf("Error failing stale imports: %v\n", err)
+		return
+	}
+	if failed > 0 {
+		log.Printf("Marked %d stale imports as failed\n", failed)
+	}
+}
+
+func (i *Interactor) runListingImport(job *ImportJob, parser ListingFeedParser, feed []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Error running import job %s: %v\n", job.ID, r)
+			i.finishListingImport(job, "An unexpected error occurred while processing the feed.")
+		}
+	}()
+
+	job.Status = ImportJobStatusProcessing
+	if err := i.services.ImportJobRepo.Update(job); err != nil {
+		log.Printf("Error updating import job %s: %v\n", job.ID, err)
+	}
+
+	records, err := parser.Parse(bytes.NewReader(feed))
+	if err != nil {
+		i.finishListingImport(job, fmt.Sprintf("Failed to parse feed: %v", err))
+		return
+	}
+	if len(records) > importMaxRows {
+		i.finishListingImport(job, fmt.Sprintf("Feeds are limited to %d listings.", importMaxRows))
+		return
+	}
+
+	resolver := &catalogResolver{aux: i.services.AuxRepo}
+	seen := make(map[string]bool)
+	stockNumbers := []string{}
+	rows := []ImportJobRow{}
+
+	job.TotalRows = len(records)
+	for idx, record := range records {
+		row := i.importListing(job, resolver, seen, record)
+		row.JobID = job.ID
+		row.Row = idx + 1
+		if row.StockNumber != "" {
+			stockNumbers = append(stockNumbers, row.StockNumber)
+		}
+
+		switch row.Result {
+		case ImportRowCreated:
+			job.CreatedCount++
+		case ImportRowUpdated:
+			job.UpdatedCount++
+		default:
+			job.FailedCount++
+		}
+		rows = append(rows, row)
+	}
+
+	if err := i.services.ImportJobRepo.AddRows(rows); err != nil {
+		log.Printf("Error saving rows of import job %s: %v\n", job.ID, err)
+	}
+
+	// A feed with failed rows or no usable rows at all is most likely broken,
+	// so it must not take the organization's other listings offline.
+	if job.FullSync && job.FailedCount == 0 && len(stockNumbers) > 0 {
+		removed, err := i.services.CarRepo.ExpireMissingStock(job.OrganizationID, stockNumbers)
+		if err != nil {
+			log.Printf("Error removing missing stock of organization %s: %v\n", job.OrganizationID, err)
+			i.finishListingImport(job, "Failed to remove listings missing from the feed.")
+			return
+		}
+		job.RemovedCount = int(removed)
+	}
+
+	i.finishListingImport(job, "")
+}
+
+func (i *Interactor) finishListingImport(job *ImportJob, failure string) {
+	job.Status = ImportJobStatusCompleted
+	if failure != "" {
+		job.Status = ImportJobStatusFailed
+		job.Error = failure
+	}
+	job.FinishedAt = time.Now()
+
+	if err := i.services.ImportJobRepo.Update(job); err != nil {
+		log.Printf("Error updating import job %s: %v\n", job.ID, err)
+	}
+}
+
+func (i *Interactor) importListing(job *ImportJob, resolver *catalogResolver, seen map[string]bool, record map[string]string) ImportJobRow {
+	row := ImportJobRow{
+		StockNumber: strings.TrimSpace(record["stock_number"]),
+		Result:      ImportRowFailed,
+	}
+	if row.StockNumber == "" {
+		row.Errors = []string{"stock_number is required."}
+		return row
+	}
+	if seen[row.StockNumber] {
+		row.Errors = []string{"stock_number appears more than once in the feed."}
+		return row
+	}
+	seen[row.StockNumber] = true
+
+	request, errors := feedRecordToRequest(record)
+	request.BrandId, request.SeriesId, request.ModelId, row.Errors = resolver.resolve(record["brand"], record["series"], record["model"])
+	row.Errors = append(row.Errors, errors...)
+	if len(row.Errors) > 0 {
+		return row
+	}
+
+	request.OrganizationID = job.OrganizationID
+	if errors := i.services.ValidateRequest(&request); errors != nil {
+		row.Errors = errors
+		return row
+	}
+
+	existing, err := i.services.CarRepo.GetByStockNumber(job.OrganizationID, row.StockNumber)
+	if err != nil {
+		log.Printf("Error fetching car by stock number: %v\n", err)
+		row.Errors = []string{"An unexpected error occurred. Please try again later."}
+		return row
+	}
+
+	if existing != nil {
+		request.ID = existing.ID
+		request.OwnerId = existing.OwnerId
+		car := request.ToCar()
+		car.SellerType = SellerTypeDealer
+		car.StockNumber = row.StockNumber
+
+		if err := i.saveListing(existing, car); err != nil {
+			log.Printf("Error updating imported car %s: %v\n", car.ID, err)
+			row.Errors = []string{"Failed to update listing."}
+			return row
+		}
+		row.Result = ImportRowUpdated
+		row.CarID = car.ID
+		return row
+	}
+
+	request.ID = uuid.New().String()
+	request.OwnerId = job.UserID
+	request.ListingDate = time.Now()
+	request.ListingNumber, err = generateSecureListingNumber(10)
+	if err != nil {
+		row.Errors = []string{err.Error()}
+		return row
+	}
+
+	car := request.ToCar()
+	car.ExpiresAt = request.ListingDate.Add(listingLifetime)
+	car.SellerType = SellerTypeDealer
+	car.StockNumber = row.StockNumber
+
+	if err := i.services.CarRepo.Create(car); err != nil {
+		log.Printf("Error creating imported car: %v\n", err)
+		row.Errors = []string{"Failed to create listing."}
+		return row
+	}
+	row.Result = ImportRowCreated
+	row.CarID = car.ID
+	return row
+}
+
+func feedRecordToRequest(record map[string]string) (CarCreateRequest, []string) {
+	var errors []string
+
+	text := func(key string) string {
+		return strings.TrimSpace(record[key])
+	}
+	number := func(key string) int {
+		value, err := strconv.Atoi(text(key))
+		if err != nil && text(key) != "" {
+			errors = append(errors, fmt.Sprintf("%s must be a whole number.", key))
+		}
+		return value
+	}
+	flag := func(key string) bool {
+		value, err := strconv.ParseBool(text(key))
+		if err != nil && text(key) != "" {
+			errors = append(errors, fmt.Sprintf("%s must be true or false.", key))
+		}
+		return value
+	}
+
+	price, err := strconv.ParseFloat(text("price"), 64)
+	if err != nil && text("price") != "" {
+		errors = append(errors, "price must be a number.")
+	}
+
+	request := CarCreateRequest{
+		Title:             text("title"),
+		Description:       text("description"),
+		Currency:          text("currency"),
+		Price:             price,
+		City:              text("city"),
+		District:          text("district"),
+		Neighborhood:      text("neighborhood"),
+		Year:              number("year"),
+		FuelType:          text("fuel_type"),
+		Transmission:      text("transmission"),
+		Mileage:           number("mileage"),
+		BodyType:          text("body_type"),
+		EnginePower:       number("engine_power"),
+		EngineVolume:      number("engine_volume"),
+		DriveType:         text("drive_type"),
+		Color:             text("color"),
+		Warranty:          flag("warranty"),
+		HeavyDamage:       flag("heavy_damage"),
+		TradeOption:       flag("trade_option"),
+		FrontBumper:       text("front_bumper"),
+		FrontHood:         text("front_hood"),
+		Roof:              text("roof"),
+		FrontRightDoor:    text("front_right_door"),
+		RearRightDoor:     text("rear_right_door"),
+		FrontLeftMudguard: text("front_left_mudguard"),
+		FrontLeftDoor:     text("front_left_door"),
+		RearLeftDoor:      text("rear_left_door"),
+		RearLeftMudguard:  text("rear_left_mudguard"),
+		RearBumper:        text("rear_bumper"),
+	}
+
+	return request, errors
+}
+
+// catalogResolver maps brand, series and model names from a feed onto catalog
+// IDs, caching each level so a feed hits the database once per distinct parent.
+type catalogResolver struct {
+	aux    AuxiliaryRepository
+	brands map[string]int
+	series map[int]map[string]int
+	models map[int]map[string]int
+}
+
+func (r *catalogResolver) resolve(brand, series, model string) (int, int, int, []string) {
+	if r.brands == nil {
+		brands, err := r.aux.GetBrands()
+		if err != nil {
+			log.Printf("Error fetching brands: %v\n", err)
+			return 0, 0, 0, []string{"failed to fetch brands"}
+		}
+		r.brands = make(map[string]int)
+		for _, b := range brands {
+			r.brands[catalogKey(b.Name)] = b.ID
+		}
+		r.series = make(map[int]map[string]int)
+		r.models = make(map[int]map[string]int)
+	}
+
+	brandID, ok := r.brands[catalogKey(brand)]
+	if !ok {
+		return 0, 0, 0, []string{fmt.Sprintf("Unknown brand %q.", brand)}
+	}
+
+	if _, ok := r.series[brandID]; !ok {
+		list, err := r.aux.GetSeriesByBrand(brandID)
+		if err != nil {
+			log.Printf("Error fetching series of brand %d: %v\n", brandID, err)
+			return 0, 0, 0, []string{"failed to fetch series"}
+		}
+		r.series[brandID] = make(map[string]int)
+		for _, s := range list {
+			r.series[brandID][catalogKey(s.Name)] = s.ID
+		}
+	}
+
+	seriesID, ok := r.series[brandID][catalogKey(series)]
+	if !ok {
+		return 0, 0, 0, []string{fmt.Sprintf("Unknown series %q for brand %q.", series, brand)}
+	}
+
+	if _, ok := r.models[seriesID]; !ok {
+		list, err := r.aux.GetModelsBySeries(seriesID)
+		if err != nil {
+			log.Printf("Error fetching models of series %d: %v\n", seriesID, err)
+			return 0, 0, 0, []string{"failed to fetch models"}
+		}
+		r.models[seriesID] = make(map[string]int)
+		for _, m := range list {
+			r.models[seriesID][catalogKey(m.Name)] = m.ID
+		}
+	}
+
+	modelID, ok := r.models[seriesID][catalogKey(model)]
+	if !ok {
+		return 0, 0, 0, []string{fmt.Sprintf("Unknown model %q for series %q.", model, series)}
+	}
+
+	return brandID, seriesID, modelID, nil
+}
+
+func catalogKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func toImportJobResponse(job *ImportJob, rows []ImportJobRow) ImportJobResponse {
+	response := ImportJobResponse{
+		ID:           job.ID,
+		Format:       job.Format,
+		FullSync:     job.FullSync,
+		Status:       job.Status,
+		TotalRows:    job.TotalRows,
+		CreatedCount: job.CreatedCount,
+		UpdatedCount: job.UpdatedCount,
+		RemovedCount: job.RemovedCount,
+		FailedCount:  job.FailedCount,
+		Error:        job.Error,
+		CreatedAt:    job.CreatedAt,
+	}
+	if !job.FinishedAt.IsZero() {
+		finishedAt := job.FinishedAt
+		response.FinishedAt = &finishedAt
+	}
+	for _, row := range rows {
+		response.Rows = append(response.Rows, ImportJobRowResponse{
+			Row:         row.Row,
+			StockNumber: row.StockNumber,
+			Result:      row.Result,
+			CarID:       row.CarID,
+			Errors:      row.Errors,
+		})
+	}
+	return response
+}
+
+func (i *Interactor) ExpireListings() {
+	expired, err := i.services.CarRepo.ExpireListings(time.Now())
+	if err != nil {
+		log.Printf("Error expiring listings: %v\n", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Expired %d listings\n", expired)
+	}
+}
+
+func (i *Interactor) RemindExpiringListings() {
+	cars, err := i.services.CarRepo.GetExpiring(time.Now().Add(listingExpiryReminder))
+	if err != nil {
+		log.Printf("Error fetching expiring listings: %v\n", err)
+		return
+	}
+
+	for idx := range cars {
+		car := &cars[idx]
+
+		locale := i.userLocale(car.OwnerId)
+		notification := &Notification{
+			UserID: car.OwnerId,
+			Type:   NotificationTypeListingExpiring,
+			Title:  i.services.EmailRenderer.Translate(locale, "notification.listing_expiring.title"),
+			Body:   i.services.EmailRenderer.Translate(locale, "notification.listing_expiring.body", car.Title, car.ExpiresAt),
+			Link:   "/cars/" + car.ID,
+			Data: ListingExpiringEventResponse{
+				CarID:     car.ID,
+				Title:     car.Title,
+				ExpiresAt: car.ExpiresAt,
+			},
+		}
+		sendEmail := i.notify(EventTypeListingExpiring, notification)
+
+		// Mark the reminder as sent before the email goes out, so a failing
+		// email does not repeat the in-app notification every hour.
+		err = i.services.CarRepo.MarkExpiryNotified(car.ID, time.Now())
+		if err != nil {
+			log.Printf("Error marking expiry reminder of car %s: %v\n", car.ID, err)
+		}
+		if !sendEmail {
+			continue
+		}
+
+		err = i.sendNotificationEmail(notification, EmailTemplateListingExpiring, map[string]interface{}{
+			"Title":     car.Title,
+			"ExpiresAt": car.ExpiresAt,
+		})
+		if err != nil {
+			log.Printf("Error sending expiry reminder for car %s: %v\n", car.ID, err)
+		}
+	}
+}
+
+func (i *Interactor) ListCars(viewerId string, page, limit, brand_id, series_id, model_id int) ([]ListCarResponse, []string) {
+	cars, err := i.services.CarRepo.GetCars(page, limit, brand_id, series_id, model_id)
+	if err != nil {
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	response, err := i.toListCarResponses(cars)
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	if err := i.markFavorites(viewerId, response); err != nil {
+		return nil, []string{"failed to fetch favorites"}
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) CreateSavedSearch(userId string, request SavedSearchRequest) (*SavedSearchResponse, []string) {
+	searches, err := i.services.SavedSearchRepo.GetByUser(userId)
+	if err != nil {
+		log.Printf("Error fetching saved searches of user %s: %v\n", userId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if len(searches) >= savedSearchLimit {
+		return nil, []string{fmt.Sprintf("You can save up to %d searches.", savedSearchLimit)}
+	}
+
+	token, err := generateToken(32)
+	if err != nil {
+		log.Printf("Error generating unsubscribe token: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	search := &SavedSearch{
+		ID:               uuid.New().String(),
+		UserID:           userId,
+		Name:             request.Name,
+		BrandID:          request.BrandID,
+		SeriesID:         request.SeriesID,
+		ModelID:          request.ModelID,
+		Frequency:        request.Frequency,
+		UnsubscribeToken: token,
+		LastRunAt:        time.Now(),
+		CreatedAt:        time.Now(),
+	}
+	err = i.services.SavedSearchRepo.Create(search)
+	if err != nil {
+		log.Printf("Error creating saved search: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	response := toSavedSearchResponse(search)
+	return &response, nil
+}
+
+func (i *Interactor) UpdateSavedSearch(userId, searchId string, request SavedSearchRequest) []string {
+	search, errors := i.getSavedSearch(userId, searchId)
+	if errors != nil {
+		return errors
+	}
+
+	if search.BrandID != request.BrandID || search.SeriesID != request.SeriesID || search.ModelID != request.ModelID {
+		search.LastRunAt = time.Now()
+	}
+	search.Name = request.Name
+	search.BrandID = request.BrandID
+	search.SeriesID = request.SeriesID
+	search.ModelID = request.ModelID
+	search.Frequency = request.Frequency
+
+	err := i.services.SavedSearchRepo.Update(search)
+	if err != nil {
+		log.Printf("Error updating saved search %s: %v\n", search.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) DeleteSavedSearch(userId, searchId string) []string {
+	search, errors := i.getSavedSearch(userId, searchId)
+	if errors != nil {
+		return errors
+	}
+
+	err := i.services.SavedSearchRepo.Delete(search.ID)
+	if err != nil {
+		log.Printf("Error deleting saved search %s: %v\n", search.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) ListSavedSearches(userId string) ([]SavedSearchResponse, []string) {
+	searches, err := i.services.SavedSearchRepo.GetByUser(userId)
+	if err != nil {
+		log.Printf("Error fetching saved searches of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch saved searches"}
+	}
+
+	response := []SavedSearchResponse{}
+	for idx := range searches {
+		response = append(response, toSavedSearchResponse(&searches[idx]))
+	}
+	return response, nil
+}
+
+func (i *Interactor) RunSavedSearch(userId, searchId string, page, limit int) ([]ListCarResponse, []string) {
+	search, errors := i.getSavedSearch(userId, searchId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	return i.ListCars(userId, page, limit, search.BrandID, search.SeriesID, search.ModelID)
+}
+
+func (i *Interactor) UnsubscribeSavedSearch(token string) []string {
+	search, err := i.services.SavedSearchRepo.GetByUnsubscribeToken(token)
+	if err != nil {
+		log.Printf("Error fetching saved search by unsubscribe token: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if search == nil {
+		return []string{"Invalid or expired unsubscribe link."}
+	}
+
+	search.Frequency = AlertFrequencyNone
+	err = i.services.SavedSearchRepo.Update(search)
+	if err != nil {
+		log.Printf("Error updating saved search %s: %v\n", search.ID, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) SendSearchAlerts() {
+	now := time.Now()
+	searches, err := i.services.SavedSearchRepo.ClaimDue(now)
+	if err != nil {
+		log.Printf("Error fetching due saved searches: %v\n", err)
+		return
+	}
+
+	for idx := range searches {
+		search := &searches[idx]
+
+		cars, err := i.services.CarRepo.GetListedBetween(search.BrandID, search.SeriesID, search.ModelID, search.LastRunAt, now, searchAlertMaxCars)
+		if err != nil {
+			log.Printf("Error running saved search %s: %v\n", search.ID, err)
+			i.releaseSavedSearch(search)
+			continue
+		}
+
+		if len(cars) > 0 {
+			err = i.sendSearchAlertEmail(search, cars)
+			if err != nil {
+				log.Printf("Error sending alert for saved search %s: %v\n", search.ID, err)
+				i.releaseSavedSearch(search)
+			}
+		}
+	}
+}
+
+// releaseSavedSearch restores the last run of a claimed search that could
+// not be processed, so the next run covers the same listings again.
+func (i *Interactor) releaseSavedSearch(search *SavedSearch) {
+	err := i.services.SavedSearchRepo.MarkRun(search.ID, search.LastRunAt)
+	if err != nil {
+		log.Printf("Error updating saved search %s: %v\n", search.ID, err)
+	}
+}
+
+func (i *Interactor) sendSearchAlertEmail(search *SavedSearch, cars []Car) error {
+	user, err := i.services.UserRepo.GetByID(search.UserID)
+	if err != nil {
+		return err
+	}
+
+	listings, err := i.toListCarResponses(cars)
+	if err != nil {
+		return err
+	}
+
+	matches := []map[string]interface{}{}
+	for _, listing := range listings {
+		matches = append(matches, map[string]interface{}{
+			"Year":   listing.Year,
+			"Brand":  listing.Brand,
+			"Series": listing.Series,
+			"Model":  listing.Model,
+			"Title":  listing.Title,
+			"Price":  fmt.Sprintf("%.2f %s", listing.Price, listing.Currency),
+			"Link":   fmt.Sprintf("%s/cars/%s", i.config.FrontendURL, listing.Id),
+		})
+	}
+
+	unsubscribeLink := fmt.Sprintf("%s/unsubscribe?token=%s", i.config.FrontendURL, url.QueryEscape(search.UnsubscribeToken))
+	oneClickLink := fmt.Sprintf("%s/saved-searches/unsubscribe?token=%s", i.config.APIURL, url.QueryEscape(search.UnsubscribeToken))
+
+	email, err := i.renderEmail(user, EmailTemplateSearchAlert, map[string]interface{}{
+		"SearchName":      search.Name,
+		"Listings":        matches,
+		"UnsubscribeLink": unsubscribeLink,
+	})
+	if err != nil {
+		return err
+	}
+	email.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + oneClickLink + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+
+	return i.services.MailGW.Send(email)
+}
+
+func (i *Interactor) getSavedSearch(userId, searchId string) (*SavedSearch, []string) {
+	search, err := i.services.SavedSearchRepo.GetByID(searchId)
+	if err != nil {
+		log.Printf("Error fetching saved search %s: %v\n", searchId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if search == nil || search.UserID != userId {
+		return nil, []string{"saved search not found"}
+	}
+	return search, nil
+}
+
+func toSavedSearchResponse(search *SavedSearch) SavedSearchResponse {
+	return SavedSearchResponse{
+		ID:        search.ID,
+		Name:      search.Name,
+		BrandID:   search.BrandID,
+		SeriesID:  search.SeriesID,
+		ModelID:   search.ModelID,
+		Frequency: search.Frequency,
+		LastRunAt: search.LastRunAt,
+		CreatedAt: search.CreatedAt,
+	}
+}
+
+func (i *Interactor) AddFavorite(userId, carId string) []string {
+	car, err := i.services.CarRepo.GetByID(carId)
+	if err != nil || car.Status != CarStatusActive {
+		return []string{"car not found"}
+	}
+
+	err = i.services.FavoriteRepo.Add(&Favorite{
+		UserID:    userId,
+		CarID:     car.ID,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error adding favorite: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) RemoveFavorite(userId, carId string) []string {
+	err := i.services.FavoriteRepo.Remove(userId, carId)
+	if err != nil {
+		log.Printf("Error removing favorite: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) ListFavorites(userId string, page, limit int) ([]FavoriteResponse, []string) {
+	favorites, err := i.services.FavoriteRepo.GetByUser(userId, page, limit)
+	if err != nil {
+		log.Printf("Error fetching favorites of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch favorites"}
+	}
+
+	carIDs := []string{}
+	for _, favorite := range favorites {
+		carIDs = append(carIDs, favorite.CarID)
+	}
+
+	cars, err := i.services.CarRepo.GetByIDs(carIDs)
+	if err != nil {
+		log.Printf("Error fetching favorite cars of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	listings, err := i.toListCarResponses(cars)
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	carMap := make(map[string]int)
+	for idx, car := range cars {
+		carMap[car.ID] = idx
+	}
+
+	isFavorite := true
+	response := []FavoriteResponse{}
+	for _, favorite := range favorites {
+		idx, ok := carMap[favorite.CarID]
+		if !ok {
+			continue
+		}
+		listing := listings[idx]
+		listing.IsFavorite = &isFavorite
+		response = append(response, FavoriteResponse{
+			ListCarResponse: listing,
+			Status:          cars[idx].Status,
+			FavoritedAt:     favorite.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) StartConversation(userId string, request ConversationCreateRequest, attachments []*multipart.FileHeader) (*MessageResponse, []string) {
+	if errors := validateMessage(request.Body, attachments); errors != nil {
+		return nil, errors
+	}
+
+	buyer, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if !buyer.EmailVerified {
+		return nil, []string{"Please verify your email address before contacting sellers."}
+	}
+
+	car, err := i.services.CarRepo.GetByID(request.CarID)
+	if err != nil || car.Status != CarStatusActive {
+		return nil, []string{"car not found"}
+	}
+
+	if _, errors := i.getPublicUser(car.OwnerId); errors != nil {
+		return nil, []string{"car not found"}
+	}
+	if car.OwnerId == buyer.ID {
+		return nil, []string{"You cannot message yourself about your own listing."}
+	}
+
+	blocked, err := i.services.UserBlockRepo.IsBlocked(buyer.ID, car.OwnerId)
+	if err != nil {
+		log.Printf("Error checking blocks of user %s: %v\n", buyer.ID, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if blocked {
+		return nil, []string{"You cannot message this seller."}
+	}
+
+	conversation, err := i.services.ConversationRepo.GetByCarAndBuyer(car.ID, buyer.ID)
+	if err != nil {
+		log.Printf("Error fetching conversation: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if conversation == nil {
+		now := time.Now()
+		conversation = &Conversation{
+			ID:            uuid.New().String(),
+			CarID:         car.ID,
+			SellerID:      car.OwnerId,
+			BuyerID:       buyer.ID,
+			LastMessageAt: now,
+			CreatedAt:     now,
+		}
+		err = i.services.ConversationRepo.Create(conversation)
+		if err != nil {
+			log.Printf("Error creating conversation: %v\n", err)
+			return nil, []string{"An unexpected error occurred. Please try again later."}
+		}
+	}
+
+	return i.sendMessage(conversation, buyer.ID, request.Body, attachments)
+}
+
+func (i *Interactor) SendMessage(userId, conversationId string, request MessageRequest, attachments []*multipart.FileHeader) (*MessageResponse, []string) {
+	if errors := validateMessage(request.Body, attachments); errors != nil {
+		return nil, errors
+	}
+
+	conversation, errors := i.getConversation(userId, conversationId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	blocked, err := i.services.UserBlockRepo.IsBlocked(userId, counterpartOf(conversation, userId))
+	if err != nil {
+		log.Printf("Error checking blocks of user %s: %v\n", userId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if blocked {
+		return nil, []string{"You can no longer send messages in this conversation."}
+	}
+
+	return i.sendMessage(conversation, userId, request.Body, attachments)
+}
+
+func (i *Interactor) sendMessage(conversation *Conversation, senderId, body string, attachments []*multipart.FileHeader) (*MessageResponse, []string) {
+	message := &Message{
+		ID:             uuid.New().String(),
+		ConversationID: conversation.ID,
+		SenderID:       senderId,
+		Body:           strings.TrimSpace(body),
+		CreatedAt:      time.Now(),
+	}
+
+	for idx, attachment := range attachments {
+		file, err := attachment.Open()
+		if err != nil {
+			i.deleteMessageAttachments(message)
+			return nil, []string{fmt.Sprintf("Failed to open attachment: %v", err)}
+		}
+
+		attachmentURL, err := i.services.CDNRepo.SaveMessageAttachment(conversation.ID, message.ID, idx, file)
+		file.Close()
+		if err != nil {
+			i.deleteMessageAttachments(message)
+			return nil, []string{fmt.Sprintf("Failed to upload attachment: %v", err)}
+		}
+		message.Attachments = append(message.Attachments, attachmentURL)
+	}
+
+	err := i.services.ConversationRepo.CreateMessage(message)
+	if err != nil {
+		log.Printf("Error sending message: %v\n", err)
+		i.deleteMessageAttachments(message)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	response := toMessageResponse(message)
+	recipientId := counterpartOf(conversation, senderId)
+	locale := i.userLocale(recipientId)
+	preview := messagePreview(message)
+	if preview == "" {
+		preview = i.services.EmailRenderer.Translate(locale, "notification.message.photo")
+	}
+	i.notify(EventTypeMessage, &Notification{
+		UserID: recipientId,
+		Type:   NotificationTypeMessage,
+		Title:  i.services.EmailRenderer.Translate(locale, "notification.message.title"),
+		Body:   preview,
+		Link:   "/conversations/" + conversation.ID,
+		Data:   response,
+	})
+	return &response, nil
+}
+
+func (i *Interactor) ListConversations(userId string, page, limit int) ([]ConversationResponse, []string) {
+	conversations, err := i.services.ConversationRepo.GetByUser(userId, page, limit)
+	if err != nil {
+		log.Printf("Error fetching conversations of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch conversations"}
+	}
+
+	conversationIDs := []string{}
+	carIDs := []string{}
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ID)
+		carIDs = append(carIDs, conversation.CarID)
+	}
+
+	lastMessages, err := i.services.ConversationRepo.GetLastMessages(conversationIDs)
+	if err != nil {
+		log.Printf("Error fetching last messages of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch conversations"}
+	}
+
+	unread, err := i.services.ConversationRepo.CountUnread(userId, conversationIDs)
+	if err != nil {
+		log.Printf("Error counting unread messages of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch conversations"}
+	}
+
+	blocked, err := i.services.UserBlockRepo.GetBlockedIDs(userId)
+	if err != nil {
+		log.Printf("Error fetching blocks of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch conversations"}
+	}
+
+	cars, err := i.services.CarRepo.GetByIDs(carIDs)
+	if err != nil {
+		log.Printf("Error fetching conversation cars of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch conversations"}
+	}
+	titles := make(map[string]string)
+	for _, car := range cars {
+		titles[car.ID] = car.Title
+	}
+
+	users := make(map[string]*User)
+	response := []ConversationResponse{}
+	for _, conversation := range conversations {
+		counterpartId := counterpartOf(&conversation, userId)
+		counterpart, ok := users[counterpartId]
+		if !ok {
+			counterpart, err = i.services.UserRepo.GetByID(counterpartId)
+			if err != nil {
+				log.Printf("Error fetching user %s: %v\n", counterpartId, err)
+				return nil, []string{"failed to fetch conversations"}
+			}
+			users[counterpartId] = counterpart
+		}
+
+		role := ParticipantRoleBuyer
+		if conversation.SellerID == userId {
+			role = ParticipantRoleSeller
+		}
+
+		item := ConversationResponse{
+			ID:       conversation.ID,
+			CarID:    conversation.CarID,
+			CarTitle: titles[conversation.CarID],
+			Role:     role,
+			Counterpart: OwnerResponse{
+				Id:            counterpart.ID,
+				FirstName:     counterpart.FirstName,
+				LastName:      counterpart.LastName,
+				PhoneVerified: counterpart.PhoneVerified,
+				CreatedAt:     counterpart.CreatedAt,
+			},
+			UnreadCount:   unread[conversation.ID],
+			Blocked:       blocked[counterpartId],
+			LastMessageAt: conversation.LastMessageAt,
+			CreatedAt:     conversation.CreatedAt,
+		}
+		if message, ok := lastMessages[conversation.ID]; ok {
+			lastMessage := toMessageResponse(&message)
+			item.LastMessage = &lastMessage
+		}
+		response = append(response, item)
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) GetMessages(userId, conversationId string, page, limit int) ([]MessageResponse, []string) {
+	conversation, errors := i.getConversation(userId, conversationId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	err := i.services.ConversationRepo.MarkRead(conversation.ID, userId, time.Now())
+	if err != nil {
+		log.Printf("Error marking conversation %s as read: %v\n", conversation.ID, err)
+	}
+
+	messages, err := i.services.ConversationRepo.GetMessages(conversation.ID, page, limit)
+	if err != nil {
+		log.Printf("Error fetching messages of conversation %s: %v\n", conversation.ID, err)
+		return nil, []string{"failed to fetch messages"}
+	}
+
+	response := []MessageResponse{}
+	for idx := range messages {
+		response = append(response, toMessageResponse(&messages[idx]))
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) GetUnreadMessageCount(userId string) (*UnreadCountResponse, []string) {
+	count, err := i.services.ConversationRepo.CountAllUnread(userId)
+	if err != nil {
+		log.Printf("Error counting unread messages of user %s: %v\n", userId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return &UnreadCountResponse{Unread: count}, nil
+}
+
+func (i *Interactor) BlockConversation(userId, conversationId string) []string {
+	conversation, errors := i.getConversation(userId, conversationId)
+	if errors != nil {
+		return errors
+	}
+
+	err := i.services.UserBlockRepo.Block(&UserBlock{
+		BlockerID: userId,
+		BlockedID: counterpartOf(conversation, userId),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error blocking user: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+func (i *Interactor) UnblockConversation(userId, conversationId string) []string {
+	conversation, errors := i.getConversation(userId, conversationId)
+	if errors != nil {
+		return errors
+	}
+
+	err := i.services.UserBlockRepo.Unblock(userId, counterpartOf(conversation, userId))
+	if err != nil {
+		log.Printf("Error unblocking user: %v\n", err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return nil
+}
+
+// SendMessageNotifications emails participants about messages that are still
+// unread after messageNotificationDelay, one email per conversation and sender.
+func (i *Interactor) SendMessageNotifications() {
+	now := time.Now()
+	messages, err := i.services.ConversationRepo.ClaimUnnotified(now.Add(-messageNotificationDelay), now)
+	if err != nil {
+		log.Printf("Error fetching unread messages: %v\n", err)
+		return
+	}
+
+	var keys []string
+	pending := make(map[string][]Message)
+	for _, message := range messages {
+		key := message.ConversationID + "/" + message.SenderID
+		if _, ok := pending[key]; !ok {
+			keys = append(keys, key)
+		}
+		pending[key] = append(pending[key], message)
+	}
+
+	for _, key := range keys {
+		group := pending[key]
+
+		conversation, err := i.services.ConversationRepo.GetByID(group[0].ConversationID)
+		if err != nil {
+			log.Printf("Error fetching conversation %s: %v\n", group[0].ConversationID, err)
+			i.releaseMessageNotifications(group)
+			continue
+		}
+
+		if conversation != nil {
+			err = i.sendUnreadMessagesEmail(conversation, group[0].SenderID, len(group))
+			if err != nil {
+				log.Printf("Error sending unread messages email for conversation %s: %v\n", conversation.ID, err)
+				i.releaseMessageNotifications(group)
+			}
+		}
+	}
+}
+
+func (i *Interactor) releaseMessageNotifications(messages []Message) {
+	messageIDs := []string{}
+	for _, message := range messages {
+		messageIDs = append(messageIDs, message.ID)
+	}
+	err := i.services.ConversationRepo.ReleaseNotified(messageIDs)
+	if err != nil {
+		log.Printf("Error releasing message notifications: %v\n", err)
+	}
+}
+
+func (i *Interactor) sendUnreadMessagesEmail(conversation *Conversation, senderId string, count int) error {
+	recipient, err := i.services.UserRepo.GetByID(counterpartOf(conversation, senderId))
+	if err != nil {
+		return err
+	}
+	if recipient.Status != AccountStatusActive || !recipient.EmailVerified {
+		return nil
+	}
+	if !i.notificationPreference(recipient.ID, NotificationTypeMessage).Email {
+		return nil
+	}
+
+	sender, err := i.services.UserRepo.GetByID(senderId)
+	if err != nil {
+		return err
+	}
+
+	car, err := i.services.CarRepo.GetByID(conversation.CarID)
+	if err != nil {
+		return err
+	}
+
+	return i.sendEmail(recipient, EmailTemplateUnreadMessages, map[string]interface{}{
+		"Sender": sender.FirstName + " " + sender.LastName,
+		"Count":  count,
+		"Title":  car.Title,
+		"Link":   fmt.Sprintf("%s/conversations/%s", i.config.FrontendURL, conversation.ID),
+	})
+}
+
+func (i *Interactor) CreateOffer(userId, carId string, request OfferRequest) (*OfferResponse, []string) {
+	buyer, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	if !buyer.EmailVerified {
+		return nil, []string{"Please verify your email address before making offers."}
+	}
+
+	car, err := i.services.CarRepo.GetByID(carId)
+	if err != nil || car.Status != CarStatusActive {
+		return nil, []string{"car not found"}
+	}
+	if car.OwnerId == buyer.ID {
+		return nil, []string{"You cannot make an offer on your own listing."}
+	}
+
+	if request.TradeInCarID == "" && request.Amount <= 0 {
+		return nil, []string{"Please enter an offer amount."}
+	}
+	if request.TradeInCarID != "" {
+		if !car.TradeOption {
+			return nil, []string{"The seller does not accept trade-ins for this listing."}
+		}
+		tradeIn, err := i.services.CarRepo.GetByID(request.TradeInCarID)
+		if err != nil || tradeIn.OwnerId != buyer.ID || tradeIn.ID == car.ID || tradeIn.Status != CarStatusActive {
+			return nil, []string{"Trade-in car not found."}
+		}
+	}
+
+	blocked, err := i.services.UserBlockRepo.IsBlocked(buyer.ID, car.OwnerId)
+	if err != nil {
+		log.Printf("Error checking blocks of user %s: %v\n", buyer.ID, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if blocked {
+		return nil, []string{"You cannot make offers to this seller."}
+	}
+
+	existing, err := i.services.OfferRepo.GetOpenByCarAndBuyer(car.ID, buyer.ID)
+	if err != nil {
+		log.Printf("Error fetching offers of user %s: %v\n", buyer.ID, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if existing != nil {
+		return nil, []string{"You already have an open offer on this listing."}
+	}
+
+	now := time.Now()
+	offer := &Offer{
+		ID:           uuid.New().String(),
+		CarID:        car.ID,
+		BuyerID:      buyer.ID,
+		SellerID:     car.OwnerId,
+		Amount:       request.Amount,
+		Currency:     car.Currency,
+		TradeInCarID: request.TradeInCarID,
+		Message:      request.Message,
+		Status:       OfferStatusPending,
+		ExpiresAt:    now.Add(offerTTL),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	err = i.services.OfferRepo.Create(offer)
+	if err != nil {
+		log.Printf("Error creating offer: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	buyerName := buyer.FirstName + " " + buyer.LastName
+	i.notifyOffer(offer, car, offer.SellerID, buyerName, "received")
+
+	return i.toOfferResponse(offer, car, userId)
+}
+
+func (i *Interactor) RespondToOffer(userId, offerId string, request OfferActionRequest) (*OfferResponse, []string) {
+	offer, errors := i.getOffer(userId, offerId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	now := time.Now()
+	status, errors := nextOfferStatus(offer, userId, request, now)
+	if errors != nil {
+		return nil, errors
+	}
+	byBuyer := userId == offer.BuyerID
+	recipientId := offer.BuyerID
+	if byBuyer {
+		recipientId = offer.SellerID
+	}
+
+	car, err := i.services.CarRepo.GetByID(offer.CarID)
+	if err != nil {
+		return nil, []string{"car not found"}
+	}
+	if request.Action != OfferActionReject {
+		if car.Status != CarStatusActive {
+			return nil, []string{"This listing is no longer available."}
+		}
+		if offer.TradeInCarID != "" {
+			tradeIn, err := i.services.CarRepo.GetByID(offer.TradeInCarID)
+			if err != nil || tradeIn.Status != CarStatusActive {
+				return nil, []string{"The trade-in car is no longer available."}
+			}
+		}
+	}
+
+	var event string
+	switch request.Action {
+	case OfferActionAccept:
+		event = "accepted"
+		if byBuyer {
+			event = "counter_accepted"
+		}
+	case OfferActionReject:
+		event = "declined"
+		if byBuyer {
+			event = "counter_declined"
+		}
+	case OfferActionCounter:
+		offer.Amount = request.Amount
+		offer.ExpiresAt = now.Add(offerTTL)
+		event = "countered"
+	}
+	previousStatus := offer.Status
+	offer.Status = status
+	offer.Message = request.Message
+	offer.UpdatedAt = now
+
+	// Accepting sells the car, so it only succeeds while the listing is
+	// still active.
+	var closed []Offer
+	if offer.Status == OfferStatusAccepted {
+		closed, err = i.services.OfferRepo.Accept(offer, previousStatus)
+	} else {
+		err = i.services.OfferRepo.Update(offer, previousStatus)
+	}
+	if err == ErrOfferConflict {
+		return nil, []string{"This offer was updated in the meantime. Please refresh and try again."}
+	}
+	if err == ErrCarNotAvailable {
+		return nil, []string{"This listing is no longer available."}
+	}
+	if err != nil {
+		log.Printf("Error updating offer %s: %v\n", offer.ID, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	i.notifyOffer(offer, car, recipientId, "", event)
+	if offer.Status == OfferStatusAccepted {
+		car.Status = CarStatusSold
+		for idx := range closed {
+			other := &closed[idx]
+			i.notifyOffer(other, car, other.BuyerID, "", "closed")
+		}
+	}
+
+	return i.toOfferResponse(offer, car, userId)
+}
+
+// nextOfferStatus is the offer state machine. The seller answers pending
+// offers and the buyer answers counteroffers; a counter hands the turn to
+// the other party until the offer is accepted, rejected or expires.
+func nextOfferStatus(offer *Offer, actorId string, request OfferActionRequest, now time.Time) (string, []string) {
+	var turnId string
+	switch offer.Status {
+	case OfferStatusPending:
+		turnId = offer.SellerID
+	case OfferStatusCountered:
+		turnId = offer.BuyerID
+	default:
+		return "", []string{"This offer is no longer open."}
+	}
+	if now.After(offer.ExpiresAt) {
+		return "", []string{"This offer has expired."}
+	}
+	if actorId != turnId {
+		return "", []string{"Please wait for the other party to respond."}
+	}
+
+	switch request.Action {
+	case OfferActionAccept:
+		return OfferStatusAccepted, nil
+	case OfferActionReject:
+		return OfferStatusRejected, nil
+	case OfferActionCounter:
+		if request.Amount <= 0 {
+			return "", []string{"Please enter a counteroffer amount."}
+		}
+		if actorId == offer.BuyerID {
+			return OfferStatusPending, nil
+		}
+		return OfferStatusCountered, nil
+	}
+	return "", []string{"Unsupported offer action."}
+}
+
+func (i *Interactor) GetOffer(userId, offerId string) (*OfferResponse, []string) {
+	offer, errors := i.getOffer(userId, offerId)
+	if errors != nil {
+		return nil, errors
+	}
+
+	car, err := i.services.CarRepo.GetByID(offer.CarID)
+	if err != nil {
+		return nil, []string{"car not found"}
+	}
+
+	return i.toOfferResponse(offer, car, userId)
+}
+
+func (i *Interactor) ListOffers(userId, role, status string, page, limit int) ([]OfferResponse, []string) {
+	if role != "" && role != ParticipantRoleBuyer && role != ParticipantRoleSeller {
+		return nil, []string{"Invalid role."}
+	}
+
+	offers, err := i.services.OfferRepo.GetByUser(userId, role, status, page, limit)
+	if err != nil {
+		log.Printf("Error fetching offers of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch offers"}
+	}
+
+	carIDs := []string{}
+	for _, offer := range offers {
+		carIDs = append(carIDs, offer.CarID)
+	}
+
+	cars, err := i.services.CarRepo.GetByIDs(carIDs)
+	if err != nil {
+		log.Printf("Error fetching offer cars of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch offers"}
+	}
+	carMap := make(map[string]*Car)
+	for idx := range cars {
+		carMap[cars[idx].ID] = &cars[idx]
+	}
+
+	response := []OfferResponse{}
+	for idx := range offers {
+		car, ok := carMap[offers[idx].CarID]
+		if !ok {
+			continue
+		}
+		item, errors := i.toOfferResponse(&offers[idx], car, userId)
+		if errors != nil {
+			return nil, errors
+		}
+		response = append(response, *item)
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) ExpireOffers() {
+	offers, err := i.services.OfferRepo.GetExpired(time.Now())
+	if err != nil {
+		log.Printf("Error fetching expired offers: %v\n", err)
+		return
+	}
+
+	for idx := range offers {
+		offer := &offers[idx]
+		previousStatus := offer.Status
+		offer.Status = OfferStatusExpired
+		offer.UpdatedAt = time.Now()
+
+		err = i.services.OfferRepo.Update(offer, previousStatus)
+		if err == ErrOfferConflict {
+			continue
+		}
+		if err != nil {
+			log.Printf("Error expiring offer %s: %v\n", offer.ID, err)
+			continue
+		}
+
+		car, err := i.services.CarRepo.GetByID(offer.CarID)
+		if err != nil {
+			log.Printf("Error fetching car %s: %v\n", offer.CarID, err)
+			continue
+		}
+
+		i.notifyOffer(offer, car, offer.BuyerID, "", "expired")
+		i.notifyOffer(offer, car, offer.SellerID, "", "expired")
+	}
+}
+
+// notifyOffer sends the in-app notification and the email for an offer
+// event, both in the recipient's locale. counterpart is the name of the user
+// who made the offer, if any.
+func (i *Interactor) notifyOffer(offer *Offer, car *Car, recipientId, counterpart, event string) {
+	recipient, err := i.services.UserRepo.GetByID(recipientId)
+	if err != nil {
+		log.Printf("Error fetching user %s: %v\n", recipientId, err)
+		return
+	}
+
+	notification := &Notification{
+		UserID: recipientId,
+		Type:   NotificationTypeOffer,
+		Title:  i.services.EmailRenderer.Translate(recipient.Locale, "offer."+event+".subject"),
+		Body:   i.services.EmailRenderer.Translate(recipient.Locale, "offer."+event+".text", counterpart, i.formatOfferAmount(recipient.Locale, offer)),
+		Link:   "/offers/" + offer.ID,
+		Data: OfferEventResponse{
+			OfferID:  offer.ID,
+			CarID:    car.ID,
+			Title:    car.Title,
+			Status:   offer.Status,
+			Amount:   offer.Amount,
+			Currency: offer.Currency,
+		},
+	}
+	if !i.notify(EventTypeOffer, notification) {
+		return
+	}
+	if recipient.Status != AccountStatusActive || !recipient.EmailVerified {
+		return
+	}
+
+	err = i.sendEmail(recipient, EmailTemplateOffer, map[string]interface{}{
+		"Event":       event,
+		"Counterpart": counterpart,
+		"Amount":      fmt.Sprintf("%.2f %s", offer.Amount, offer.Currency),
+		"TradeIn":     offer.TradeInCarID != "",
+		"Title":       car.Title,
+		"Link":        fmt.Sprintf("%s/offers/%s", i.config.FrontendURL, offer.ID),
+	})
+	if err != nil {
+		log.Printf("Error sending offer email: %v\n", err)
+	}
+}
+
+func (i *Interactor) getOffer(userId, offerId string) (*Offer, []string) {
+	offer, err := i.services.OfferRepo.GetByID(offerId)
+	if err != nil {
+		log.Printf("Error fetching offer %s: %v\n", offerId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if offer == nil || (offer.BuyerID != userId && offer.SellerID != userId) {
+		return nil, []string{"offer not found"}
+	}
+	return offer, nil
+}
+
+func (i *Interactor) toOfferResponse(offer *Offer, car *Car, viewerId string) (*OfferResponse, []string) {
+	buyer, err := i.services.UserRepo.GetByID(offer.BuyerID)
+	if err != nil {
+		log.Printf("Error fetching user %s: %v\n", offer.BuyerID, err)
+		return nil, []string{"failed to fetch offer"}
+	}
+
+	role := ParticipantRoleBuyer
+	if offer.SellerID == viewerId {
+		role = ParticipantRoleSeller
+	}
+
+	response := &OfferResponse{
+		ID:       offer.ID,
+		CarID:    car.ID,
+		CarTitle: car.Title,
+		Role:     role,
+		Buyer: OwnerResponse{
+			Id:            buyer.ID,
+			FirstName:     buyer.FirstName,
+			LastName:      buyer.LastName,
+			PhoneVerified: buyer.PhoneVerified,
+			CreatedAt:     buyer.CreatedAt,
+		},
+		SellerID:  offer.SellerID,
+		Amount:    offer.Amount,
+		Currency:  offer.Currency,
+		Message:   offer.Message,
+		Status:    offer.Status,
+		ExpiresAt: offer.ExpiresAt,
+		CreatedAt: offer.CreatedAt,
+		UpdatedAt: offer.UpdatedAt,
+	}
+
+	if offer.TradeInCarID != "" {
+		tradeIn, err := i.services.CarRepo.GetByID(offer.TradeInCarID)
+		if err == nil {
+			listings, err := i.toListCarResponses([]Car{*tradeIn})
+			if err != nil {
+				return nil, []string{"failed to fetch brands"}
+			}
+			response.TradeIn = &listings[0]
+		}
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) formatOfferAmount(locale string, offer *Offer) string {
+	amount := fmt.Sprintf("%.2f %s", offer.Amount, offer.Currency)
+	if offer.TradeInCarID != "" {
+		amount = i.services.EmailRenderer.Translate(locale, "offer.trade_in", amount)
+	}
+	return amount
+}
+
+func (i *Interactor) SubscribeEvents(userId string) (<-chan Event, func()) {
+	return i.services.EventBroker.Subscribe(userId)
+}
+
+// IssueEventTicket hands out a short-lived, single-use ticket for opening the
+// event stream, so browsers never have to put the bearer token in the URL.
+func (i *Interactor) IssueEventTicket(userId, token string, issuedAt int64) (*EventTicketResponse, []string) {
+	ticket, err := generateToken(32)
+	if err != nil {
+		log.Printf("Error generating event ticket: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	session := &EventSession{UserID: userId, Token: token, IssuedAt: issuedAt}
+	if err := i.services.TokenRepo.SaveEventTicket(hashCode(ticket), session, eventTicketTTL); err != nil {
+		log.Printf("Error saving event ticket of user %s: %v\n", userId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return &EventTicketResponse{
+		Ticket:    ticket,
+		ExpiresAt: time.Now().Add(eventTicketTTL),
+	}, nil
+}
+
+func (i *Interactor) RedeemEventTicket(ticket string) (*EventSession, []string) {
+	session, err := i.services.TokenRepo.ConsumeEventTicket(hashCode(ticket))
+	if err != nil {
+		log.Printf("Error redeeming event ticket: %v\n", err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if session == nil {
+		return nil, []string{"Invalid or expired event ticket."}
+	}
+
+	active, errorMessages := i.IsEventSessionActive(session)
+	if errorMessages != nil {
+		return nil, errorMessages
+	}
+	if !active {
+		return nil, []string{"Invalid or expired event ticket."}
+	}
+
+	return session, nil
+}
+
+// IsEventSessionActive reports whether an open event stream may keep
+// receiving events: the token must not have been logged out or revoked and
+// the account must still be active.
+func (i *Interactor) IsEventSessionActive(session *EventSession) (bool, []string) {
+	isBlacklisted, errorMessages := i.IsTokenBlackListed(session.Token)
+	if errorMessages != nil {
+		return false, errorMessages
+	}
+	if isBlacklisted {
+		return false, nil
+	}
+
+	isRevoked, errorMessages := i.IsTokenRevoked(session.UserID, session.IssuedAt)
+	if errorMessages != nil {
+		return false, errorMessages
+	}
+	if isRevoked {
+		return false, nil
+	}
+
+	user, err := i.services.UserRepo.GetByID(session.UserID)
+	if errors.Is(err, ErrUserNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, []string{"Failed to check account status: " + err.Error()}
+	}
+
+	return user.Status == AccountStatusActive, nil
+}
+
+func (i *Interactor) ListNotifications(userId string, unreadOnly bool, page, limit int) (*NotificationListResponse, []string) {
+	notifications, err := i.services.NotificationRepo.GetByUser(userId, unreadOnly, page, limit)
+	if err != nil {
+		log.Printf("Error fetching notifications of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch notifications"}
+	}
+
+	unread, err := i.services.NotificationRepo.CountUnread(userId)
+	if err != nil {
+		log.Printf("Error counting notifications of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch notifications"}
+	}
+
+	response := &NotificationListResponse{
+		Notifications: []NotificationResponse{},
+		Unread:        unread,
+	}
+	for _, notification := range notifications {
+		response.Notifications = append(response.Notifications, NotificationResponse{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Link:      notification.Link,
+			Data:      notification.Data,
+			Read:      !notification.ReadAt.IsZero(),
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) MarkNotificationRead(userId, notificationId string) []string {
+	found, err := i.services.NotificationRepo.MarkRead(userId, notificationId, time.Now())
+	if err != nil {
+		log.Printf("Error marking notification %s as read: %v\n", notificationId, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	if !found {
+		return []string{"notification not found"}
+	}
+	return nil
+}
+
+func (i *Interactor) MarkAllNotificationsRead(userId string) []string {
+	err := i.services.NotificationRepo.MarkAllRead(userId, time.Now())
+	if err != nil {
+		log.Printf("Error marking notifications of user %s as read: %v\n", userId, err)
+		return []string{"An unexpected error occurred. Please try again later."}
+	}
+	return nil
+}
+
+func (i *Interactor) GetNotificationPreferences(userId string) ([]NotificationPreferenceResponse, []string) {
+	stored, err := i.services.NotificationRepo.GetPreferences(userId)
+	if err != nil {
+		log.Printf("Error fetching notification preferences of user %s: %v\n", userId, err)
+		return nil, []string{"failed to fetch notification preferences"}
+	}
+
+	preferences := make(map[string]NotificationPreference)
+	for _, preference := range stored {
+		preferences[preference.Type] = preference
+	}
+
+	response := []NotificationPreferenceResponse{}
+	for _, notificationType := range NotificationTypes {
+		preference, ok := preferences[notificationType]
+		if !ok {
+			preference = defaultNotificationPreference(userId, notificationType)
+		}
+		response = append(response, NotificationPreferenceResponse{
+			Type:  notificationType,
+			InApp: preference.InApp,
+			Email: preference.Email,
+		})
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) UpdateNotificationPreferences(userId string, request NotificationPreferencesRequest) ([]NotificationPreferenceResponse, []string) {
+	preferences := []NotificationPreference{}
+	for _, preference := range request.Preferences {
+		preferences = append(preferences, NotificationPreference{
+			UserID: userId,
+			Type:   preference.Type,
+			InApp:  preference.InApp,
+			Email:  preference.Email,
+		})
+	}
+
+	err := i.services.NotificationRepo.SavePreferences(preferences)
+	if err != nil {
+		log.Printf("Error saving notification preferences of user %s: %v\n", userId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return i.GetNotificationPreferences(userId)
+}
+
+// notify is the hook every domain event goes through. It pushes the live
+// event, stores an in-app notification unless the user turned that type off,
+// and reports whether the user also wants the event by email.
+func (i *Interactor) notify(eventType string, notification *Notification) bool {
+	i.publishEvent(notification.UserID, eventType, notification.Data)
+
+	preference := i.notificationPreference(notification.UserID, notification.Type)
+	if preference.InApp {
+		notification.ID = uuid.New().String()
+		notification.CreatedAt = time.Now()
+		err := i.services.NotificationRepo.Create(notification)
+		if err != nil {
+			log.Printf("Error creating %s notification: %v\n", notification.Type, err)
+		}
+	}
+
+	return preference.Email
+}
+
+func (i *Interactor) notificationPreference(userId, notificationType string) NotificationPreference {
+	preference, err := i.services.NotificationRepo.GetPreference(userId, notificationType)
+	if err != nil {
+		log.Printf("Error fetching notification preference of user %s: %v\n", userId, err)
+	}
+	if preference == nil {
+		return defaultNotificationPreference(userId, notificationType)
+	}
+	return *preference
+}
+
+func defaultNotificationPreference(userId, notificationType string) NotificationPreference {
+	return NotificationPreference{
+		UserID: userId,
+		Type:   notificationType,
+		InApp:  true,
+		Email:  true,
+	}
+}
+
+func (i *Interactor) sendNotificationEmail(notification *Notification, template string, data map[string]interface{}) error {
+	user, err := i.services.UserRepo.GetByID(notification.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Status != AccountStatusActive || !user.EmailVerified {
+		return nil
+	}
+
+	data["Link"] = i.config.FrontendURL + notification.Link
+	return i.sendEmail(user, template, data)
+}
+
+// userLocale returns the locale in-app notifications for the user are
+// written in; they share the catalog with the emails.
+func (i *Interactor) userLocale(userId string) string {
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		log.Printf("Error fetching user %s: %v\n", userId, err)
+		return DefaultLocale
+	}
+	return user.Locale
+}
+
+// renderEmail renders a transactional email addressed to the user, in the
+// user's locale.
+func (i *Interactor) renderEmail(user *User, template string, data map[string]interface{}) (*Email, error) {
+	data["Name"] = user.FirstName
+	email, err := i.services.EmailRenderer.Render(template, user.Locale, data)
+	if err != nil {
+		return nil, err
+	}
+	email.To = user.Email
+	return email, nil
+}
+
+func (i *Interactor) sendEmail(user *User, template string, data map[string]interface{}) error {
+	email, err := i.renderEmail(user, template, data)
+	if err != nil {
+		return err
+	}
+	return i.services.MailGW.Send(email)
+}
+
+func (i *Interactor) publishEvent(userId, eventType string, data interface{}) {
+	err := i.services.EventBroker.Publish(&Event{
+		Type:      eventType,
+		UserID:    userId,
+		Data:      data,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("Error publishing %s event: %v\n", eventType, err)
+	}
+}
+
+// deleteMessageAttachments removes the files already stored for a message
+// that could not be sent.
+func (i *Interactor) deleteMessageAttachments(message *Message) {
+	for _, attachment := range message.Attachments {
+		err := i.services.CDNRepo.DeleteMessageAttachment(message.ConversationID, path.Base(attachment))
+		if err != nil {
+			log.Printf("Error deleting attachment %s: %v\n", attachment, err)
+		}
+	}
+}
+
+func (i *Interactor) getConversation(userId, conversationId string) (*Conversation, []string) {
+	conversation, err := i.services.ConversationRepo.GetByID(conversationId)
+	if err != nil {
+		log.Printf("Error fetching conversation %s: %v\n", conversationId, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+	if conversation == nil || (conversation.BuyerID != userId && conversation.SellerID != userId) {
+		return nil, []string{"conversation not found"}
+	}
+	return conversation, nil
+}
+
+func validateMessage(body string, attachments []*multipart.FileHeader) []string {
+	if strings.TrimSpace(body) == "" && len(attachments) == 0 {
+		return []string{"A message must contain text or an attachment."}
+	}
+	if len(attachments) > messageAttachmentLimit {
+		return []string{fmt.Sprintf("A message can have at most %d attachments.", messageAttachmentLimit)}
+	}
+	return nil
+}
+
+func messagePreview(message *Message) string {
+	preview := []rune(message.Body)
+	if len(preview) > messagePreviewLength {
+		return string(preview[:messagePreviewLength]) + "..."
+	}
+	return message.Body
+}
+
+func counterpartOf(conversation *Conversation, userId string) string {
+	if conversation.BuyerID == userId {
+		return conversation.SellerID
+	}
+	return conversation.BuyerID
+}
+
+func toMessageResponse(message *Message) MessageResponse {
+	response := MessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Body:           message.Body,
+		Attachments:    message.Attachments,
+		CreatedAt:      message.CreatedAt,
+	}
+	if !message.ReadAt.IsZero() {
+		readAt := message.ReadAt
+		response.ReadAt = &readAt
 	}
+	return response
+}
 
-	//for _, v := range request.Images {
-	//	// v byte -> image
-	//}
+func (i *Interactor) markFavorites(viewerId string, listings []ListCarResponse) error {
+	if viewerId == "" {
+		return nil
+	}
+
+	carIDs := []string{}
+	for _, listing := range listings {
+		carIDs = append(carIDs, listing.Id)
+	}
 
-	err = i.services.CarRepo.Create(request.ToCar())
+	favorites, err := i.services.FavoriteRepo.GetFavoriteCarIDs(viewerId, carIDs)
 	if err != nil {
-		return []string{err.Error()}
+		log.Printf("Error fetching favorites of user %s: %v\n", viewerId, err)
+		return err
 	}
 
+	for idx := range listings {
+		isFavorite := favorites[listings[idx].Id]
+		listings[idx].IsFavorite = &isFavorite
+	}
 	return nil
 }
 
-func (i *Interactor) ListCars(page, limit, brand_id, series_id, model_id int) ([]ListCarResponse, []string) {
-	cars, err := i.services.CarRepo.GetCars(page, limit, brand_id, series_id, model_id)
-	if err != nil {
-		return nil, []string{"failed to fetch cars"}
-	}
-
+func (i *Interactor) toListCarResponses(cars []Car) ([]ListCarResponse, error) {
 	brands, err := i.GetBrands()
 	if err != nil {
-		return nil, []string{"failed to fetch brands"}
+		return nil, err
 	}
 
 	brandMap := make(map[int]BrandResponse)
@@ -340,23 +4729,20 @@ func (i *Interactor) ListCars(page, limit, brand_id, series_id, model_id int) ([
 	return response, nil
 }
 
-func (i *Interactor) GetCarDetail(id string) (*CarDetailResponse, []string) {
+func (i *Interactor) GetCarDetail(viewerId, id string) (*CarDetailResponse, []string) {
 	car, err := i.services.CarRepo.GetByID(id)
 	if err != nil {
 		return nil, []string{"failed to fetch cars"}
 	}
+	if car.Status != CarStatusActive && car.Status != CarStatusSold {
+		return nil, []string{"car not found"}
+	}
 	owner, err := i.services.UserRepo.GetByID(car.OwnerId)
 	if err != nil {
 		return nil, []string{"Error fetching user by"}
 	}
-
-	ownerResponse := OwnerResponse{
-		Id:          owner.ID,
-		FirstName:   owner.FirstName,
-		LastName:    owner.LastName,
-		CountryCode: owner.CountryCode,
-		PhoneNumber: owner.PhoneNumber,
-		CreatedAt:   owner.CreatedAt,
+	if owner.Status == AccountStatusBanned || !owner.DeletionRequestedAt.IsZero() {
+		return nil, []string{"car not found"}
 	}
 
 	brands, err := i.GetBrands()
@@ -364,6 +4750,69 @@ func (i *Interactor) GetCarDetail(id string) (*CarDetailResponse, []string) {
 		return nil, []string{"failed to fetch brands"}
 	}
 
+	err = i.services.CarRepo.IncrementViewCount(car.ID)
+	if err != nil {
+		log.Printf("Error incrementing view count of car %s: %v\n", car.ID, err)
+	}
+
+	var organization *Organization
+	if car.OrganizationID != "" {
+		organization, err = i.services.OrganizationRepo.GetByID(car.OrganizationID)
+		if err != nil {
+			log.Printf("Error fetching organization %s: %v\n", car.OrganizationID, err)
+		}
+	}
+
+	carDetailResponse := toCarDetailResponse(car, owner, organization, brands)
+
+	changes, err := i.services.PriceHistoryRepo.GetByCar(car.ID)
+	if err != nil {
+		log.Printf("Error fetching price history of car %s: %v\n", car.ID, err)
+		return nil, []string{"failed to fetch price history"}
+	}
+	carDetailResponse.PriceHistory = toPriceHistory(car, changes)
+
+	if viewerId != "" {
+		favorites, err := i.services.FavoriteRepo.GetFavoriteCarIDs(viewerId, []string{car.ID})
+		if err != nil {
+			log.Printf("Error fetching favorites of user %s: %v\n", viewerId, err)
+			return nil, []string{"failed to fetch favorites"}
+		}
+		isFavorite := favorites[car.ID]
+		carDetailResponse.IsFavorite = &isFavorite
+	}
+	return &carDetailResponse, nil
+}
+
+func toPriceHistory(car *Car, changes []PriceChange) []PricePointResponse {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	history := []PricePointResponse{{
+		Price:     changes[0].OldPrice,
+		Currency:  changes[0].OldCurrency,
+		ChangedAt: car.ListingDate,
+	}}
+	for _, change := range changes {
+		history = append(history, PricePointResponse{
+			Price:     change.NewPrice,
+			Currency:  change.NewCurrency,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	return history
+}
+
+func toCarDetailResponse(car *Car, owner *User, organization *Organization, brands []BrandResponse) CarDetailResponse {
+	ownerResponse := OwnerResponse{
+		Id:            owner.ID,
+		FirstName:     owner.FirstName,
+		LastName:      owner.LastName,
+		PhoneVerified: owner.PhoneVerified,
+		CreatedAt:     owner.CreatedAt,
+	}
+
 	brandMap := make(map[int]BrandResponse)
 	seriesMap := make(map[int]string)
 	modelMap := make(map[int]string)
@@ -377,9 +4826,17 @@ func (i *Interactor) GetCarDetail(id string) (*CarDetailResponse, []string) {
 		}
 	}
 
-	carDetailResponse := &CarDetailResponse{
+	var organizationResponse *OrganizationSummaryResponse
+	if organization != nil {
+		summary := toOrganizationSummaryResponse(organization)
+		organizationResponse = &summary
+	}
+
+	return CarDetailResponse{
 		ID:                car.ID,
+		Status:            car.Status,
 		Owner:             ownerResponse,
+		Organization:      organizationResponse,
 		Title:             car.Title,
 		Description:       car.Description,
 		Currency:          car.Currency,
@@ -389,9 +4846,9 @@ func (i *Interactor) GetCarDetail(id string) (*CarDetailResponse, []string) {
 		Neighborhood:      car.Neighborhood,
 		ListingNumber:     car.ListingNumber,
 		ListingDate:       car.ListingDate,
-		Brand:             brandMap[car.BrandId].Name, 
-		Series:            seriesMap[car.SeriesId],   
-		Model:             modelMap[car.ModelId],     
+		Brand:             brandMap[car.BrandId].Name,
+		Series:            seriesMap[car.SeriesId],
+		Model:             modelMap[car.ModelId],
 		Year:              car.Year,
 		FuelType:          car.FuelType,
 		Transmission:      car.Transmission,
@@ -417,8 +4874,336 @@ func (i *Interactor) GetCarDetail(id string) (*CarDetailResponse, []string) {
 		RearBumper:        car.RearBumper,
 		Images:            []string{},
 	}
+}
+
+func (i *Interactor) SearchUsers(query, role, status string, page, limit int) ([]AdminUserResponse, []string) {
+	users, err := i.services.UserRepo.Search(query, role, status, page, limit)
+	if err != nil {
+		log.Printf("Error searching users: %v\n", err)
+		return nil, []string{"failed to search users"}
+	}
+
+	response := []AdminUserResponse{}
+	for _, user := range users {
+		response = append(response, toAdminUserResponse(&user))
+	}
+
+	return response, nil
+}
+
+func (i *Interactor) GetUserForAdmin(id string) (*AdminUserDetailResponse, []string) {
+	user, err := i.services.UserRepo.GetByID(id)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	cars, err := i.services.CarRepo.GetByOwner(id)
+	if err != nil {
+		log.Printf("Error fetching cars of user %s: %v\n", id, err)
+		return nil, []string{"failed to fetch cars"}
+	}
+
+	carResponses, err := i.toListCarResponses(cars)
+	if err != nil {
+		return nil, []string{"failed to fetch brands"}
+	}
+
+	changes, err := i.services.UserRepo.GetStatusChanges(id)
+	if err != nil {
+		log.Printf("Error fetching status history of user %s: %v\n", id, err)
+		return nil, []string{"failed to fetch status history"}
+	}
+
+	history := []UserStatusChangeResponse{}
+	for _, change := range changes {
+		history = append(history, UserStatusChangeResponse{
+			ChangedBy: change.ChangedBy,
+			OldStatus: change.OldStatus,
+			NewStatus: change.NewStatus,
+			Reason:    change.Reason,
+			CreatedAt: change.CreatedAt,
+		})
+	}
+
+	return &AdminUserDetailResponse{
+		User:          toAdminUserResponse(user),
+		Cars:          carResponses,
+		StatusHistory: history,
+	}, nil
+}
+
+func (i *Interactor) ChangeUserStatus(adminId, adminRole, userId string, request UserStatusChangeRequest) []string {
+	if adminId == userId {
+		return []string{"You cannot change your own status."}
+	}
+
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if user.Role == UserRoleAdmin && adminRole != UserRoleAdmin {
+		return []string{"Only admins can change the status of another admin."}
+	}
+
+	if user.Status == AccountStatusDeleted {
+		return []string{"This account has been deleted."}
+	}
+
+	if user.Status == request.Status {
+		return []string{"User already has status " + request.Status + "."}
+	}
+
+	change := &UserStatusChange{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		ChangedBy: adminId,
+		OldStatus: user.Status,
+		NewStatus: request.Status,
+		Reason:    request.Reason,
+		CreatedAt: time.Now(),
+	}
+
+	err = i.services.UserRepo.UpdateStatus(user.ID, request.Status)
+	if err != nil {
+		log.Printf("Error updating status of user %s: %v\n", userId, err)
+		return []string{"failed to update user status"}
+	}
+
+	err = i.services.UserRepo.AddStatusChange(change)
+	if err != nil {
+		log.Printf("Error recording status change of user %s: %v\n", userId, err)
+	}
+
+	if request.Status != AccountStatusActive {
+		err = i.services.TokenRepo.RevokeUserTokens(userId, time.Now())
+		if err != nil {
+			log.Printf("Error revoking tokens of user %s: %v\n", userId, err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Interactor) GetEmailTemplates() []string {
+	return i.services.EmailRenderer.Templates()
+}
+
+func (i *Interactor) PreviewEmail(template, locale string) (*EmailPreviewResponse, []string) {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	supported := false
+	for _, l := range Locales {
+		if l == locale {
+			supported = true
+		}
+	}
+	if !supported {
+		return nil, []string{"Unsupported locale."}
+	}
+
+	user := &User{
+		FirstName: "Jane",
+		Email:     "jane.doe@example.com",
+		Locale:    locale,
+	}
+	email, err := i.renderEmail(user, template, i.emailPreviewData(template))
+	if err != nil {
+		if errors.Is(err, ErrEmailTemplateNotFound) {
+			return nil, []string{"email template not found"}
+		}
+		log.Printf("Error rendering email preview %s: %v\n", template, err)
+		return nil, []string{"An unexpected error occurred. Please try again later."}
+	}
+
+	return &EmailPreviewResponse{
+		Template: template,
+		Locale:   locale,
+		Subject:  email.Subject,
+		Text:     email.Text,
+		HTML:     email.HTML,
+	}, nil
+}
+
+// emailPreviewData returns sample data for the admin preview of a template.
+func (i *Interactor) emailPreviewData(template string) map[string]interface{} {
+	title := "2019 Volkswagen Golf 1.5 TSI Highline"
+	carLink := i.config.FrontendURL + "/cars/preview"
+
+	switch template {
+	case EmailTemplateVerifyEmail:
+		return map[string]interface{}{"Link": i.config.FrontendURL + "/verify-email?token=preview&email=jane.doe%40example.com"}
+	case EmailTemplateAccountLocked:
+		return map[string]interface{}{"Minutes": int(loginAccountLockTTL.Minutes())}
+	case EmailTemplatePasswordReset:
+		return map[string]interface{}{
+			"Link":    i.config.FrontendURL + "/reset-password?token=preview&email=jane.doe%40example.com",
+			"Minutes": int(passwordResetTTL.Minutes()),
+		}
+	case EmailTemplateEmailChangeConfirm:
+		return map[string]interface{}{"Link": i.config.FrontendURL + "/confirm-email-change?token=preview&user_id=preview"}
+	case EmailTemplateEmailChangeNotice:
+		return map[string]interface{}{"NewEmail": "jane.new@example.com"}
+	case EmailTemplateAccountDeletion:
+		return map[string]interface{}{"DeletionDate": time.Now().Add(accountDeletionGracePeriod)}
+	case EmailTemplateAccountDeletionCode:
+		return map[string]interface{}{
+			"Code":    "482913",
+			"Minutes": int(accountDeletionCodeTTL.Minutes()),
+		}
+	case EmailTemplatePriceDrop:
+		return map[string]interface{}{
+			"Title":    title,
+			"OldPrice": "18500.00 EUR",
+			"NewPrice": "17250.00 EUR",
+			"Link":     carLink,
+		}
+	case EmailTemplateSearchAlert:
+		return map[string]interface{}{
+			"SearchName": "Compact cars under 20k",
+			"Listings": []map[string]interface{}{
+				{"Year": 2019, "Brand": "Volkswagen", "Series": "Golf", "Model": "1.5 TSI", "Title": title, "Price": "17250.00 EUR", "Link": carLink},
+				{"Year": 2020, "Brand": "Renault", "Series": "Clio", "Model": "1.0 TCe", "Title": "2020 Renault Clio 1.0 TCe Touch", "Price": "14900.00 EUR", "Link": carLink},
+			},
+			"UnsubscribeLink": i.config.FrontendURL + "/unsubscribe?token=preview",
+		}
+	case EmailTemplateUnreadMessages:
+		return map[string]interface{}{
+			"Sender": "John Smith",
+			"Count":  3,
+			"Title":  title,
+			"Link":   i.config.FrontendURL + "/conversations/preview",
+		}
+	case EmailTemplateOffer:
+		return map[string]interface{}{
+			"Event":       "received",
+			"Counterpart": "John Smith",
+			"Amount":      "16800.00 EUR",
+			"TradeIn":     true,
+			"Title":       title,
+			"Link":        i.config.FrontendURL + "/offers/preview",
+		}
+	case EmailTemplateListingApproved, EmailTemplateListingRejected:
+		return map[string]interface{}{
+			"Title":  title,
+			"Reason": "The photos were reviewed and match the vehicle description.",
+			"Link":   carLink,
+		}
+	case EmailTemplateListingExpiring:
+		return map[string]interface{}{
+			"Title":     title,
+			"ExpiresAt": time.Now().Add(listingExpiryReminder),
+			"Link":      carLink,
+		}
+	case EmailTemplateOrganizationInvitation:
+		return map[string]interface{}{
+			"Inviter":      "John Smith",
+			"Organization": "Carwise Motors",
+			"Role":         OrganizationRoleManager,
+			"Days":         int(organizationInvitationTTL.Hours() / 24),
+			"Link":         i.config.FrontendURL + "/profile/invitations",
+		}
+	}
+	return map[string]interface{}{}
+}
+
+func (i *Interactor) ModerateListing(carId string, request ListingModerationRequest) []string {
+	car, err := i.services.CarRepo.GetByID(carId)
+	if err != nil {
+		return []string{"car not found"}
+	}
+
+	if car.Status == request.Status {
+		return []string{"Listing already has status " + request.Status + "."}
+	}
+	if request.Status == CarStatusActive && car.Status != CarStatusRemoved {
+		return []string{"Only removed listings can be restored."}
+	}
+
+	err = i.services.CarRepo.SetStatus(car.ID, request.Status)
+	if err != nil {
+		log.Printf("Error moderating car %s: %v\n", car.ID, err)
+		return []string{"failed to update listing status"}
+	}
+
+	template := EmailTemplateListingApproved
+	notificationType := NotificationTypeListingApproved
+	if request.Status == CarStatusRemoved {
+		template = EmailTemplateListingRejected
+		notificationType = NotificationTypeListingRejected
+	}
+
+	locale := i.userLocale(car.OwnerId)
+	notification := &Notification{
+		UserID: car.OwnerId,
+		Type:   notificationType,
+		Title:  i.services.EmailRenderer.Translate(locale, "notification."+template+".title"),
+		Body:   i.services.EmailRenderer.Translate(locale, "notification."+template+".body", car.Title, request.Reason),
+		Link:   "/cars/" + car.ID,
+		Data: ListingModerationEventResponse{
+			CarID:  car.ID,
+			Title:  car.Title,
+			Status: request.Status,
+			Reason: request.Reason,
+		},
+	}
+	if i.notify(EventTypeListingModerated, notification) {
+		err = i.sendNotificationEmail(notification, template, map[string]interface{}{
+			"Title":  car.Title,
+			"Reason": request.Reason,
+		})
+		if err != nil {
+			log.Printf("Error sending moderation email: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+func (i *Interactor) ChangeUserRole(adminId, userId string, request UserRoleChangeRequest) []string {
+	if adminId == userId {
+		return []string{"You cannot change your own role."}
+	}
+
+	user, err := i.services.UserRepo.GetByID(userId)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	if user.Role == request.Role {
+		return []string{"User already has role " + request.Role + "."}
+	}
+
+	err = i.services.UserRepo.UpdateRole(user.ID, request.Role)
+	if err != nil {
+		log.Printf("Error updating role of user %s: %v\n", userId, err)
+		return []string{"failed to update user role"}
+	}
+
+	err = i.services.TokenRepo.RevokeUserTokens(userId, time.Now())
+	if err != nil {
+		log.Printf("Error revoking tokens of user %s: %v\n", userId, err)
+	}
+
+	return nil
+}
 
-	return carDetailResponse, nil
+func toAdminUserResponse(user *User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:          user.ID,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		ImageUrl:    user.ImageUrl,
+		CountryCode: user.CountryCode,
+		PhoneNumber: user.PhoneNumber,
+		Email:       user.Email,
+		Role:        user.Role,
+		Status:      user.Status,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		LastLogin:   user.LastLogin,
+	}
 }
 
 func hashPassword(password string) (string, error) {
@@ -443,6 +5228,173 @@ func generateToken(length int) (string, error) {
 	return base64.URLEncoding.EncodeToString(token), nil
 }
 
+func generatePKCEVerifier() (string, error) {
+	verifier := make([]byte, 32)
+	_, err := rand.Read(verifier)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(verifier), nil
+}
+
+func unusablePasswordHash() (string, error) {
+	password, err := generateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	return hashPassword(password)
+}
+
+func generateNumericCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+
+	return string(code), nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func normalizePhoneNumber(countryCode, phoneNumber string) (string, string, error) {
+	cc := digitsOnly(countryCode)
+	cc = strings.TrimPrefix(cc, "00")
+
+	number := digitsOnly(phoneNumber)
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(phoneNumber), "+"):
+		number = strings.TrimPrefix(number, cc)
+	case strings.HasPrefix(number, "00"):
+		number = strings.TrimPrefix(strings.TrimPrefix(number, "00"), cc)
+	case cc != "" && !strings.HasPrefix(number, "0") && strings.HasPrefix(number, cc) && len(number)-len(cc) >= 7:
+		// Without a trunk prefix, a number that starts with the country code
+		// and is long enough without it was entered in international form.
+		number = strings.TrimPrefix(number, cc)
+	}
+	number = strings.TrimLeft(number, "0")
+
+	if len(cc) < 1 || len(cc) > 3 || cc[0] == '0' {
+		return "", "", errors.New("invalid country code")
+	}
+	if len(cc)+len(number) < 8 || len(cc)+len(number) > 15 {
+		return "", "", errors.New("invalid phone number length")
+	}
+
+	return cc, number, nil
+}
+
+func formatE164(countryCode, phoneNumber string) string {
+	return "+" + countryCode + phoneNumber
+}
+
+func digitsOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func generateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret), nil
+}
+
+func totpURI(secret, account string) string {
+	label := url.PathEscape(totpIssuer + ":" + account)
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", totpIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+func totpCode(secret string, counter int64) (string, error) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod), nil
+}
+
+func validateTOTP(secret, code string, at time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if secret == "" || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := at.Unix() / totpPeriod
+	for skew := int64(-totpSkew); skew <= totpSkew; skew++ {
+		expected, err := totpCode(secret, current+skew)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return current + skew, true
+		}
+	}
+
+	return 0, false
+}
+
+func generateRecoveryCodes(count int) ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, count)
+	hashes := make([]string, 0, count)
+	for n := 0; n < count; n++ {
+		raw := make([]byte, 7)
+		_, err := rand.Read(raw)
+		if err != nil {
+			return nil, nil, err
+		}
+		encoded := strings.ToLower(encoding.EncodeToString(raw))[:10]
+		code := encoded[:5] + "-" + encoded[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashCode(normalizeRecoveryCode(code)))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
 func generateSecureListingNumber(length int) (string, error) {
 	letters := []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
 	randPart := make([]rune, length)
@@ -0,0 +1,454 @@
+package carwise
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizePhoneNumber(t *testing.T) {
+	tests := []struct {
+		name        string
+		countryCode string
+		phoneNumber string
+		wantCode    string
+		wantNumber  string
+		wantErr     bool
+	}{
+		{name: "plain", countryCode: "90", phoneNumber: "5321234567", wantCode: "90", wantNumber: "5321234567"},
+		{name: "plus country code", countryCode: "+90", phoneNumber: "532 123 45 67", wantCode: "90", wantNumber: "5321234567"},
+		{name: "double zero prefix", countryCode: "0090", phoneNumber: "5321234567", wantCode: "90", wantNumber: "5321234567"},
+		{name: "national trunk prefix", countryCode: "90", phoneNumber: "0532 123 45 67", wantCode: "90", wantNumber: "5321234567"},
+		{name: "international format", countryCode: "90", phoneNumber: "+90 (532) 123-45-67", wantCode: "90", wantNumber: "5321234567"},
+		{name: "country code without plus", countryCode: "90", phoneNumber: "905321234567", wantCode: "90", wantNumber: "5321234567"},
+		{name: "international dialing prefix", countryCode: "90", phoneNumber: "00 90 532 123 45 67", wantCode: "90", wantNumber: "5321234567"},
+		{name: "short number starting with country code", countryCode: "90", phoneNumber: "9012345", wantCode: "90", wantNumber: "9012345"},
+		{name: "three digit country code", countryCode: "380", phoneNumber: "501234567", wantCode: "380", wantNumber: "501234567"},
+		{name: "missing country code", countryCode: "", phoneNumber: "5321234567", wantErr: true},
+		{name: "country code too long", countryCode: "1234", phoneNumber: "5321234567", wantErr: true},
+		{name: "country code starting with zero", countryCode: "0", phoneNumber: "5321234567", wantErr: true},
+		{name: "too short", countryCode: "90", phoneNumber: "12345", wantErr: true},
+		{name: "too long", countryCode: "90", phoneNumber: "1234567890123456", wantErr: true},
+		{name: "no digits", countryCode: "90", phoneNumber: "phone", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, number, err := normalizePhoneNumber(tt.countryCode, tt.phoneNumber)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q %q", code, number)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if code != tt.wantCode || number != tt.wantNumber {
+				t.Errorf("got %q %q, want %q %q", code, number, tt.wantCode, tt.wantNumber)
+			}
+		})
+	}
+}
+
+func TestFormatE164(t *testing.T) {
+	if got := formatE164("90", "5321234567"); got != "+905321234567" {
+		t.Errorf("got %q, want %q", got, "+905321234567")
+	}
+}
+
+// rfc6238Secret is the SHA1 test key from RFC 6238, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	// The RFC lists 8-digit codes; ours are the last six digits.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "287082"},
+		{unix: 1111111109, want: "081804"},
+		{unix: 1111111111, want: "050471"},
+		{unix: 1234567890, want: "005924"},
+		{unix: 2000000000, want: "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := totpCode(rfc6238Secret, tt.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("at %d: got %q, want %q", tt.unix, got, tt.want)
+		}
+	}
+
+	if _, err := totpCode("not base32!", 1); err == nil {
+		t.Error("expected an error for an invalid secret")
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	step := now.Unix() / totpPeriod
+	code := func(counter int64) string {
+		c, err := totpCode(rfc6238Secret, counter)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantOK   bool
+		wantStep int64
+	}{
+		{name: "current step", secret: rfc6238Secret, code: code(step), wantOK: true, wantStep: step},
+		{name: "lowercase secret", secret: strings.ToLower(rfc6238Secret), code: code(step), wantOK: true, wantStep: step},
+		{name: "surrounding spaces", secret: rfc6238Secret, code: " " + code(step) + " ", wantOK: true, wantStep: step},
+		{name: "previous step", secret: rfc6238Secret, code: code(step - 1), wantOK: true, wantStep: step - 1},
+		{name: "next step", secret: rfc6238Secret, code: code(step + 1), wantOK: true, wantStep: step + 1},
+		{name: "outside skew", secret: rfc6238Secret, code: code(step - 2)},
+		{name: "wrong code", secret: rfc6238Secret, code: "000000"},
+		{name: "short code", secret: rfc6238Secret, code: code(step)[:5]},
+		{name: "no secret", secret: "", code: code(step)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, ok := validateTOTP(tt.secret, tt.code, now)
+			if ok != tt.wantOK {
+				t.Fatalf("got ok=%v, want %v", ok, tt.wantOK)
+			}
+			if ok && gotStep != tt.wantStep {
+				t.Errorf("got step %d, want %d", gotStep, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("got secret of length %d, want 32", len(secret))
+	}
+	if _, err := totpCode(secret, 1); err != nil {
+		t.Errorf("generated secret is not usable: %v", err)
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(totpURI(rfc6238Secret, "jane@example.com"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Errorf("got %s://%s, want otpauth://totp", uri.Scheme, uri.Host)
+	}
+	if uri.Path != "/Carwise:jane@example.com" {
+		t.Errorf("got label %q", uri.Path)
+	}
+	query := uri.Query()
+	for key, want := range map[string]string{"secret": rfc6238Secret, "issuer": "Carwise", "digits": "6", "period": "30", "algorithm": "SHA1"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s: got %q, want %q", key, got, want)
+		}
+	}
+}
+
+type staticFeedParser []map[string]string
+
+func (p staticFeedParser) Parse(feed io.Reader) ([]map[string]string, error) {
+	return p, nil
+}
+
+type catalogAuxRepository struct{}
+
+func (catalogAuxRepository) GetBrands() ([]Brand, error) {
+	return []Brand{{ID: 1, Name: "BMW"}}, nil
+}
+
+func (catalogAuxRepository) GetSeriesByBrand(brandID int) ([]Series, error) {
+	return []Series{{ID: 2, Name: "3 Series"}}, nil
+}
+
+func (catalogAuxRepository) GetModelsBySeries(seriesID int) ([]Model, error) {
+	return []Model{{ID: 3, Name: "Sedan"}}, nil
+}
+
+type importCarRepository struct {
+	CarRepository
+	stock   map[string]*Car
+	expired [][]string
+}
+
+func (r *importCarRepository) GetByStockNumber(organizationID, stockNumber string) (*Car, error) {
+	return r.stock[stockNumber], nil
+}
+
+func (r *importCarRepository) UpdateListing(car *Car) error {
+	return nil
+}
+
+func (r *importCarRepository) ExpireMissingStock(organizationID string, stockNumbers []string) (int64, error) {
+	r.expired = append(r.expired, stockNumbers)
+	return 1, nil
+}
+
+type importJobRepository struct {
+	ImportJobRepository
+}
+
+func (r *importJobRepository) Update(job *ImportJob) error {
+	return nil
+}
+
+func (r *importJobRepository) AddRows(rows []ImportJobRow) error {
+	return nil
+}
+
+func TestRunListingImportFullSync(t *testing.T) {
+	listing := func(stockNumber string) map[string]string {
+		return map[string]string{"stock_number": stockNumber, "brand": "BMW", "series": "3 Series", "model": "Sedan"}
+	}
+
+	tests := []struct {
+		name        string
+		records     []map[string]string
+		wantExpired [][]string
+		wantRemoved int
+	}{
+		{name: "no records", records: nil},
+		{name: "records without stock numbers", records: []map[string]string{{"brand": "BMW"}, {"brand": "Audi"}}},
+		{name: "failed row", records: []map[string]string{listing("STK-1"), {"stock_number": "STK-2", "brand": "Unknown"}}},
+		{name: "all rows imported", records: []map[string]string{listing("STK-1")}, wantExpired: [][]string{{"STK-1"}}, wantRemoved: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cars := &importCarRepository{stock: map[string]*Car{"STK-1": {ID: "car-1", StockNumber: "STK-1"}}}
+			interactor := NewInteractor(Services{
+				AuxRepo:         catalogAuxRepository{},
+				CarRepo:         cars,
+				ImportJobRepo:   &importJobRepository{},
+				ValidateRequest: func(request interface{}) []string { return nil },
+			}, Config{})
+
+			job := &ImportJob{ID: "job-1", OrganizationID: "org-1", FullSync: true}
+			interactor.runListingImport(job, staticFeedParser(tt.records), nil)
+
+			if job.Status != ImportJobStatusCompleted {
+				t.Fatalf("got status %q (%s), want %q", job.Status, job.Error, ImportJobStatusCompleted)
+			}
+			if !reflect.DeepEqual(cars.expired, tt.wantExpired) {
+				t.Errorf("expired %v, want %v", cars.expired, tt.wantExpired)
+			}
+			if job.RemovedCount != tt.wantRemoved {
+				t.Errorf("removed %d, want %d", job.RemovedCount, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+type sessionTokenRepository struct {
+	TokenRepository
+	blacklisted string
+	revokedAt   time.Time
+}
+
+func (r *sessionTokenRepository) IsTokenBlackListed(token string) (bool, error) {
+	return token == r.blacklisted, nil
+}
+
+func (r *sessionTokenRepository) GetUserTokensRevokedAt(userID string) (time.Time, error) {
+	return r.revokedAt, nil
+}
+
+type statusUserRepository struct {
+	UserRepository
+	users map[string]*User
+}
+
+func (r *statusUserRepository) GetByID(id string) (*User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func TestIsEventSessionActive(t *testing.T) {
+	issuedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	users := map[string]*User{
+		"active": {ID: "active", Status: AccountStatusActive},
+		"banned": {ID: "banned", Status: AccountStatusBanned},
+	}
+
+	tests := []struct {
+		name        string
+		session     EventSession
+		blacklisted string
+		revokedAt   time.Time
+		want        bool
+	}{
+		{name: "active", session: EventSession{UserID: "active", Token: "t", IssuedAt: issuedAt.Unix()}, want: true},
+		{name: "revoked before issue", session: EventSession{UserID: "active", Token: "t", IssuedAt: issuedAt.Unix()}, revokedAt: issuedAt.Add(-time.Hour), want: true},
+		{name: "logged out", session: EventSession{UserID: "active", Token: "t", IssuedAt: issuedAt.Unix()}, blacklisted: "t"},
+		{name: "revoked after issue", session: EventSession{UserID: "active", Token: "t", IssuedAt: issuedAt.Unix()}, revokedAt: issuedAt.Add(time.Hour)},
+		{name: "banned", session: EventSession{UserID: "banned", Token: "t", IssuedAt: issuedAt.Unix()}},
+		{name: "deleted", session: EventSession{UserID: "deleted", Token: "t", IssuedAt: issuedAt.Unix()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interactor := NewInteractor(Services{
+				UserRepo:  &statusUserRepository{users: users},
+				TokenRepo: &sessionTokenRepository{blacklisted: tt.blacklisted, revokedAt: tt.revokedAt},
+			}, Config{})
+
+			got, errorMessages := interactor.IsEventSessionActive(&tt.session)
+			if errorMessages != nil {
+				t.Fatalf("unexpected errors: %v", errorMessages)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextOfferStatus(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	offer := func(status string, expiresAt time.Time) *Offer {
+		return &Offer{BuyerID: "buyer", SellerID: "seller", Status: status, ExpiresAt: expiresAt}
+	}
+	open := now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		offer      *Offer
+		actor      string
+		request    OfferActionRequest
+		wantStatus string
+		wantError  string
+	}{
+		{name: "seller accepts", offer: offer(OfferStatusPending, open), actor: "seller", request: OfferActionRequest{Action: OfferActionAccept}, wantStatus: OfferStatusAccepted},
+		{name: "seller rejects", offer: offer(OfferStatusPending, open), actor: "seller", request: OfferActionRequest{Action: OfferActionReject}, wantStatus: OfferStatusRejected},
+		{name: "seller counters", offer: offer(OfferStatusPending, open), actor: "seller", request: OfferActionRequest{Action: OfferActionCounter, Amount: 900}, wantStatus: OfferStatusCountered},
+		{name: "buyer accepts counter", offer: offer(OfferStatusCountered, open), actor: "buyer", request: OfferActionRequest{Action: OfferActionAccept}, wantStatus: OfferStatusAccepted},
+		{name: "buyer counters back", offer: offer(OfferStatusCountered, open), actor: "buyer", request: OfferActionRequest{Action: OfferActionCounter, Amount: 950}, wantStatus: OfferStatusPending},
+		{name: "buyer answers own offer", offer: offer(OfferStatusPending, open), actor: "buyer", request: OfferActionRequest{Action: OfferActionAccept}, wantError: "Please wait for the other party to respond."},
+		{name: "seller answers own counter", offer: offer(OfferStatusCountered, open), actor: "seller", request: OfferActionRequest{Action: OfferActionAccept}, wantError: "Please wait for the other party to respond."},
+		{name: "counter without amount", offer: offer(OfferStatusPending, open), actor: "seller", request: OfferActionRequest{Action: OfferActionCounter}, wantError: "Please enter a counteroffer amount."},
+		{name: "unknown action", offer: offer(OfferStatusPending, open), actor: "seller", request: OfferActionRequest{Action: "withdraw"}, wantError: "Unsupported offer action."},
+		{name: "expired but not yet swept", offer: offer(OfferStatusPending, now.Add(-time.Minute)), actor: "seller", request: OfferActionRequest{Action: OfferActionAccept}, wantError: "This offer has expired."},
+		{name: "already accepted", offer: offer(OfferStatusAccepted, open), actor: "seller", request: OfferActionRequest{Action: OfferActionReject}, wantError: "This offer is no longer open."},
+		{name: "already rejected", offer: offer(OfferStatusRejected, open), actor: "buyer", request: OfferActionRequest{Action: OfferActionAccept}, wantError: "This offer is no longer open."},
+		{name: "expired status", offer: offer(OfferStatusExpired, open), actor: "seller", request: OfferActionRequest{Action: OfferActionAccept}, wantError: "This offer is no longer open."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, errorMessages := nextOfferStatus(tt.offer, tt.actor, tt.request, now)
+			if tt.wantError != "" {
+				if len(errorMessages) != 1 || errorMessages[0] != tt.wantError {
+					t.Fatalf("got errors %v, want %q", errorMessages, tt.wantError)
+				}
+				return
+			}
+			if errorMessages != nil {
+				t.Fatalf("unexpected errors: %v", errorMessages)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("got status %q, want %q", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+type expiringCarRepository struct {
+	CarRepository
+	cars     []Car
+	notified []string
+}
+
+func (r *expiringCarRepository) GetExpiring(before time.Time) ([]Car, error) {
+	return r.cars, nil
+}
+
+func (r *expiringCarRepository) MarkExpiryNotified(id string, at time.Time) error {
+	r.notified = append(r.notified, id)
+	return nil
+}
+
+type recordingNotificationRepository struct {
+	NotificationRepository
+	created []Notification
+}
+
+func (r *recordingNotificationRepository) Create(notification *Notification) error {
+	r.created = append(r.created, *notification)
+	return nil
+}
+
+func (r *recordingNotificationRepository) GetPreference(userID, notificationType string) (*NotificationPreference, error) {
+	return nil, nil
+}
+
+type discardEventBroker struct {
+	EventBroker
+}
+
+func (discardEventBroker) Publish(event *Event) error {
+	return nil
+}
+
+type staticEmailRenderer struct {
+	EmailRenderer
+}
+
+func (staticEmailRenderer) Render(name, locale string, data map[string]interface{}) (*Email, error) {
+	return &Email{Subject: name}, nil
+}
+
+func (staticEmailRenderer) Translate(locale, key string, args ...interface{}) string {
+	return key
+}
+
+type failingMailGateway struct{}
+
+func (failingMailGateway) Send(email *Email) error {
+	return errors.New("smtp unavailable")
+}
+
+func TestRemindExpiringListingsMarksFailedEmails(t *testing.T) {
+	cars := &expiringCarRepository{cars: []Car{{ID: "car-1", OwnerId: "owner", Title: "BMW 320i", ExpiresAt: time.Now().Add(time.Hour)}}}
+	notifications := &recordingNotificationRepository{}
+	interactor := NewInteractor(Services{
+		UserRepo:         &statusUserRepository{users: map[string]*User{"owner": {ID: "owner", Status: AccountStatusActive, EmailVerified: true}}},
+		CarRepo:          cars,
+		NotificationRepo: notifications,
+		EventBroker:      discardEventBroker{},
+		EmailRenderer:    staticEmailRenderer{},
+		MailGW:           failingMailGateway{},
+	}, Config{})
+
+	interactor.RemindExpiringListings()
+
+	if !reflect.DeepEqual(cars.notified, []string{"car-1"}) {
+		t.Errorf("marked %v, want [car-1]", cars.notified)
+	}
+	if len(notifications.created) != 1 {
+		t.Errorf("created %d notifications, want 1", len(notifications.created))
+	}
+}
@@ -43,6 +43,20 @@ const (
 	DriveTypeAllWheelDrive   = "All-Wheel Drive"
 )
 
+const (
+	CarStatusActive  = "Active"
+	CarStatusPaused  = "Paused"
+	CarStatusSold    = "Sold"
+	CarStatusExpired = "Expired"
+	CarStatusRemoved = "Removed"
+)
+
+const (
+	ListingActionPause    = "pause"
+	ListingActionRenew    = "renew"
+	ListingActionMarkSold = "mark_sold"
+)
+
 type Car struct {
 	ID                string
 	OwnerId           string
@@ -81,6 +95,192 @@ type Car struct {
 	RearLeftDoor      string
 	RearLeftMudguard  string
 	RearBumper        string
+	OrganizationID    string
+	StockNumber       string
+	Status            string
+	ExpiresAt         time.Time
+	ViewCount         int
+}
+
+type ContactReveal struct {
+	ID        string
+	CarID     string
+	SellerID  string
+	ViewerID  string
+	IP        string
+	Masked    bool
+	CreatedAt time.Time
+}
+
+type Organization struct {
+	ID          string
+	Name        string
+	TaxNumber   string
+	TaxOffice   string
+	Address     string
+	City        string
+	CountryCode string
+	PhoneNumber string
+	LogoUrl     string
+	Verified    bool
+	VerifiedAt  time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type OrganizationMember struct {
+	OrganizationID string
+	UserID         string
+	Role           string
+	CreatedAt      time.Time
+}
+
+type OrganizationInvitation struct {
+	ID             string
+	OrganizationID string
+	Email          string
+	Role           string
+	InvitedBy      string
+	CreatedAt      time.Time
+	ExpiresAt      time.Time
+}
+
+type SavedSearch struct {
+	ID               string
+	UserID           string
+	Name             string
+	BrandID          int
+	SeriesID         int
+	ModelID          int
+	Frequency        string
+	UnsubscribeToken string
+	LastRunAt        time.Time
+	CreatedAt        time.Time
+}
+
+type PriceChange struct {
+	CarID       string
+	OldPrice    float64
+	OldCurrency string
+	NewPrice    float64
+	NewCurrency string
+	ChangedAt   time.Time
+}
+
+type Favorite struct {
+	UserID    string
+	CarID     string
+	CreatedAt time.Time
+}
+
+type Conversation struct {
+	ID            string
+	CarID         string
+	SellerID      string
+	BuyerID       string
+	LastMessageAt time.Time
+	CreatedAt     time.Time
+}
+
+type Message struct {
+	ID             string
+	ConversationID string
+	SenderID       string
+	Body           string
+	Attachments    []string
+	ReadAt         time.Time
+	NotifiedAt     time.Time
+	CreatedAt      time.Time
+}
+
+type UserBlock struct {
+	BlockerID string
+	BlockedID string
+	CreatedAt time.Time
+}
+
+type ResponseStats struct {
+	Conversations int
+	Responded     int
+}
+
+type Offer struct {
+	ID           string
+	CarID        string
+	BuyerID      string
+	SellerID     string
+	Amount       float64
+	Currency     string
+	TradeInCarID string
+	Message      string
+	Status       string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type Notification struct {
+	ID        string
+	UserID    string
+	Type      string
+	Title     string
+	Body      string
+	Link      string
+	Data      interface{}
+	ReadAt    time.Time
+	CreatedAt time.Time
+}
+
+type NotificationPreference struct {
+	UserID string
+	Type   string
+	InApp  bool
+	Email  bool
+}
+
+type Event struct {
+	Type      string
+	UserID    string
+	Data      interface{}
+	CreatedAt time.Time
+}
+
+type EventSession struct {
+	UserID   string
+	Token    string
+	IssuedAt int64
+}
+
+type ImportJob struct {
+	ID             string
+	OrganizationID string
+	UserID         string
+	Format         string
+	FullSync       bool
+	Status         string
+	TotalRows      int
+	CreatedCount   int
+	UpdatedCount   int
+	RemovedCount   int
+	FailedCount    int
+	Error          string
+	CreatedAt      time.Time
+	FinishedAt     time.Time
+}
+
+type ImportJobRow struct {
+	JobID       string
+	Row         int
+	StockNumber string
+	Result      string
+	CarID       string
+	Errors      []string
+}
+
+type SellerStats struct {
+	ActiveListings    int
+	SoldListings      int
+	HasDealerListings bool
 }
 
 type Images struct {
@@ -108,26 +308,295 @@ type Model struct {
 }
 
 const (
-	UserRoleAdmin   = "Admin"
-	UserRoleRegular = "Regular"
+	UserRoleAdmin         = "Admin"
+	UserRoleModerator     = "Moderator"
+	UserRoleCatalogEditor = "CatalogEditor"
+	UserRoleRegular       = "Regular"
 	//------------------------
 	AccountStatusActive   = "Active"
 	AccountStatusInactive = "Inactive"
 	AccountStatusBanned   = "Banned"
+	AccountStatusDeleted  = "Deleted"
+)
+
+const (
+	OrganizationRoleOwner       = "Owner"
+	OrganizationRoleManager     = "Manager"
+	OrganizationRoleSalesperson = "Salesperson"
 )
 
+const (
+	OrganizationVerificationVerified = "verified"
+	OrganizationVerificationPending  = "pending"
+)
+
+const (
+	AlertFrequencyInstant = "instant"
+	AlertFrequencyDaily   = "daily"
+	AlertFrequencyWeekly  = "weekly"
+	AlertFrequencyNone    = "none"
+)
+
+const (
+	ParticipantRoleBuyer  = "buyer"
+	ParticipantRoleSeller = "seller"
+)
+
+const (
+	EventTypeMessage          = "message"
+	EventTypePriceDrop        = "price_drop"
+	EventTypeListingModerated = "listing_moderated"
+	EventTypeOffer            = "offer"
+	EventTypeListingExpiring  = "listing_expiring"
+)
+
+const (
+	NotificationTypeMessage         = "message"
+	NotificationTypeOffer           = "offer"
+	NotificationTypePriceDrop       = "price_drop"
+	NotificationTypeListingApproved = "listing_approved"
+	NotificationTypeListingRejected = "listing_rejected"
+	NotificationTypeListingExpiring = "listing_expiring"
+)
+
+var NotificationTypes = []string{
+	NotificationTypeMessage,
+	NotificationTypeOffer,
+	NotificationTypePriceDrop,
+	NotificationTypeListingApproved,
+	NotificationTypeListingRejected,
+	NotificationTypeListingExpiring,
+}
+
+const (
+	EmailTemplateVerifyEmail            = "verify_email"
+	EmailTemplateAccountLocked          = "account_locked"
+	EmailTemplatePasswordReset          = "password_reset"
+	EmailTemplateEmailChangeConfirm     = "email_change_confirm"
+	EmailTemplateEmailChangeNotice      = "email_change_notice"
+	EmailTemplateAccountDeletion        = "account_deletion"
+	EmailTemplateAccountDeletionCode    = "account_deletion_code"
+	EmailTemplatePriceDrop              = "price_drop"
+	EmailTemplateSearchAlert            = "search_alert"
+	EmailTemplateUnreadMessages         = "unread_messages"
+	EmailTemplateOffer                  = "offer"
+	EmailTemplateListingApproved        = "listing_approved"
+	EmailTemplateListingRejected        = "listing_rejected"
+	EmailTemplateListingExpiring        = "listing_expiring"
+	EmailTemplateOrganizationInvitation = "organization_invitation"
+)
+
+const (
+	LocaleEnglish = "en"
+	LocaleTurkish = "tr"
+	DefaultLocale = LocaleEnglish
+)
+
+var Locales = []string{
+	LocaleEnglish,
+	LocaleTurkish,
+}
+
+const (
+	OfferStatusPending   = "pending"
+	OfferStatusCountered = "countered"
+	OfferStatusAccepted  = "accepted"
+	OfferStatusRejected  = "rejected"
+	OfferStatusExpired   = "expired"
+)
+
+const (
+	OfferActionAccept  = "accept"
+	OfferActionReject  = "reject"
+	OfferActionCounter = "counter"
+)
+
+const (
+	ImportFormatCSV = "csv"
+	ImportFormatXML = "xml"
+)
+
+const (
+	ImportJobStatusQueued     = "Queued"
+	ImportJobStatusProcessing = "Processing"
+	ImportJobStatusCompleted  = "Completed"
+	ImportJobStatusFailed     = "Failed"
+)
+
+const (
+	ImportRowCreated = "Created"
+	ImportRowUpdated = "Updated"
+	ImportRowFailed  = "Failed"
+)
+
+const (
+	PermissionCarsModerate        = "cars:moderate"
+	PermissionUsersView           = "users:view"
+	PermissionUsersBan            = "users:ban"
+	PermissionUsersRole           = "users:role"
+	PermissionCatalogWrite        = "catalog:write"
+	PermissionOrganizationsVerify = "organizations:verify"
+	PermissionEmailsPreview       = "emails:preview"
+)
+
+var RolePermissions = map[string][]string{
+	UserRoleAdmin: {
+		PermissionCarsModerate,
+		PermissionUsersView,
+		PermissionUsersBan,
+		PermissionUsersRole,
+		PermissionCatalogWrite,
+		PermissionOrganizationsVerify,
+		PermissionEmailsPreview,
+	},
+	UserRoleModerator: {
+		PermissionCarsModerate,
+		PermissionUsersView,
+		PermissionUsersBan,
+		PermissionOrganizationsVerify,
+	},
+	UserRoleCatalogEditor: {
+		PermissionCatalogWrite,
+	},
+	UserRoleRegular: {},
+}
+
+func HasPermission(role, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
 type User struct {
-	ID           string
-	FirstName    string
-	LastName     string
-	ImageUrl     string
-	CountryCode  string
-	PhoneNumber  string
-	Email        string
-	PasswordHash string
-	Role         string
-	Status       string
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
-	LastLogin    time.Time
+	ID                  string
+	FirstName           string
+	LastName            string
+	ImageUrl            string
+	CountryCode         string
+	PhoneNumber         string
+	Email               string
+	PasswordHash        string
+	Role                string
+	Status              string
+	EmailVerified       bool
+	PhoneVerified       bool
+	TwoFactorEnabled    bool
+	TwoFactorSecret     string
+	ShowPhoneNumber     bool
+	Locale              string
+	DeletionRequestedAt time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	LastLogin           time.Time
+}
+
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodTwoFactor = "two_factor"
+	LoginMethodSocial    = "social"
+)
+
+const (
+	LoginOutcomeSuccess   = "success"
+	LoginOutcomeFailure   = "failure"
+	LoginOutcomeChallenge = "challenge"
+)
+
+const (
+	LoginReasonUnknownAccount    = "unknown_account"
+	LoginReasonInvalidPassword   = "invalid_password"
+	LoginReasonInvalidCode       = "invalid_code"
+	LoginReasonLocked            = "locked"
+	LoginReasonBanned            = "banned"
+	LoginReasonSuspended         = "suspended"
+	LoginReasonTwoFactorRequired = "two_factor_required"
+)
+
+type LoginEvent struct {
+	ID        string
+	UserID    string
+	Email     string
+	IP        string
+	UserAgent string
+	Method    string
+	Outcome   string
+	Reason    string
+	CreatedAt time.Time
+}
+
+type UserStatusChange struct {
+	ID        string
+	UserID    string
+	ChangedBy string
+	OldStatus string
+	NewStatus string
+	Reason    string
+	CreatedAt time.Time
+}
+
+type PhoneOTP struct {
+	Phone    string
+	CodeHash string
+	Attempts int
+}
+
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration
+}
+
+type Email struct {
+	To          string
+	Subject     string
+	Text        string
+	HTML        string
+	Headers     map[string]string
+	Attachments []EmailAttachment
+}
+
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+type EmailChange struct {
+	NewEmail string
+	CodeHash string
+}
+
+type UserIdentity struct {
+	ID        string
+	UserID    string
+	Provider  string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FirstName     string
+	LastName      string
+	ImageUrl      string
+}
+
+type OAuthState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
 }
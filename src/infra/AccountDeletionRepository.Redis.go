@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type AccountDeletionRepository struct {
+	client *redis.Client
+}
+
+func NewAccountDeletionRepository() *AccountDeletionRepository {
+	return &AccountDeletionRepository{client: ConnectRedis()}
+}
+
+func (r *AccountDeletionRepository) SaveDeletionCode(userID, codeHash string, ttl time.Duration) error {
+	key := fmt.Sprintf("account-deletion:%s", userID)
+	attemptsKey := fmt.Sprintf("account-deletion-attempts:%s", userID)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, codeHash, ttl)
+		pipe.Del(ctx, attemptsKey)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save account deletion code to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *AccountDeletionRepository) VerifyDeletionCode(userID, codeHash string) (bool, error) {
+	key := fmt.Sprintf("account-deletion:%s", userID)
+	storedHash, err := r.client.Get(context.Background(), key).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve account deletion code from Redis: %v", err)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(storedHash), []byte(codeHash)) == 1, nil
+}
+
+func (r *AccountDeletionRepository) IncrementAttempts(userID string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("account-deletion-attempts:%s", userID)
+	attempts, err := r.client.Incr(context.Background(), key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment account deletion attempts in Redis: %v", err)
+	}
+
+	if attempts == 1 {
+		err = r.client.Expire(context.Background(), key, window).Err()
+		if err != nil {
+			return 0, fmt.Errorf("failed to set account deletion attempts window in Redis: %v", err)
+		}
+	}
+
+	return attempts, nil
+}
+
+func (r *AccountDeletionRepository) DeleteDeletionCode(userID string) error {
+	key := fmt.Sprintf("account-deletion:%s", userID)
+	attemptsKey := fmt.Sprintf("account-deletion-attempts:%s", userID)
+	err := r.client.Del(context.Background(), key, attemptsKey).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete account deletion code from Redis: %v", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package infra
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -8,12 +9,17 @@ import (
 )
 
 type CDNRepository struct {
-	basePath string
+	basePath       string
+	attachmentPath string
 }
 
+// NewCDNRepository stores public images under /images, which is served as is,
+// and message attachments under /attachments, which is only reachable
+// through the conversation endpoints.
 func NewCDNRepository() *CDNRepository {
 	return &CDNRepository{
-		basePath: "/images",
+		basePath:       "/images",
+		attachmentPath: "/attachments",
 	}
 }
 
@@ -39,3 +45,92 @@ func (r *CDNRepository) SaveUserAvatar(userID string, image io.Reader) (string,
 
 	return fmt.Sprintf("%s/users/%s/avatar.png", r.basePath, userID), nil
 }
+
+func (r *CDNRepository) SaveOrganizationLogo(organizationID string, image io.Reader) (string, error) {
+	dirPath := filepath.Join(r.basePath, "organizations", organizationID)
+	err := os.MkdirAll(dirPath, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	filePath := filepath.Join(dirPath, "logo.png")
+
+	out, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, image)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/organizations/%s/logo.png", r.basePath, organizationID), nil
+}
+
+func (r *CDNRepository) SaveMessageAttachment(conversationID, messageID string, index int, image io.Reader) (string, error) {
+	dirPath := filepath.Join(r.attachmentPath, "conversations", conversationID)
+	err := os.MkdirAll(dirPath, os.ModePerm)
+	if err != nil {
+		return "", err
+	}
+
+	fileName := fmt.Sprintf("%s-%d.png", messageID, index)
+	filePath := filepath.Join(dirPath, fileName)
+
+	out, err := os.Create(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, image)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("/conversations/%s/attachments/%s", conversationID, fileName), nil
+}
+
+func (r *CDNRepository) OpenUserAvatar(userID string) (io.ReadCloser, error) {
+	file, err := os.Open(filepath.Join(r.basePath, "users", userID, "avatar.png"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func (r *CDNRepository) OpenMessageAttachment(conversationID, fileName string) (io.ReadCloser, error) {
+	file, err := os.Open(r.messageAttachmentPath(conversationID, fileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+func (r *CDNRepository) DeleteMessageAttachment(conversationID, fileName string) error {
+	err := os.Remove(r.messageAttachmentPath(conversationID, fileName))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (r *CDNRepository) messageAttachmentPath(conversationID, fileName string) string {
+	return filepath.Join(r.attachmentPath, "conversations", filepath.Base(conversationID), filepath.Base(fileName))
+}
+
+func (r *CDNRepository) DeleteUserAvatar(userID string) error {
+	return os.RemoveAll(filepath.Join(r.basePath, "users", userID))
+}
+
+func (r *CDNRepository) DeleteConversationAttachments(conversationID string) error {
+	return os.RemoveAll(filepath.Join(r.attachmentPath, "conversations", conversationID))
+}
@@ -5,8 +5,76 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 )
 
+const carColumns = `
+			id, 
+			owner_id, 
+			COALESCE(organization_id, ''), 
+			COALESCE(stock_number, ''), 
+			title, 
+			description, 
+			currency, 
+			price, 
+			city, 
+			district, 
+			neighborhood, 
+			listing_number, 
+			listing_date, 
+			brand_id, 
+			series_id, 
+			model_id, 
+			year, 
+			fuel_type, 
+			transmission, 
+			mileage, 
+			body_type, 
+			engine_power, 
+			engine_volume, 
+			drive_type, 
+			color, 
+			warranty, 
+			heavy_damage, 
+			seller_type, 
+			trade_option, 
+			front_bumper, 
+			front_hood, 
+			roof, 
+			front_right_door, 
+			rear_right_door, 
+			front_left_mudguard, 
+			front_left_door, 
+			rear_left_door, 
+			rear_left_mudguard, 
+			rear_bumper, 
+			status, 
+			expires_at, 
+			view_count`
+
+var managerRoles = []string{carwise.OrganizationRoleOwner, carwise.OrganizationRoleManager}
+
+// managedByCondition matches the cars a user may manage: their own personal
+// listings, and the listings of organizations they currently belong to,
+// either their own or any of them when they are an owner or manager.
+func managedByCondition(userParam, rolesParam string) string {
+	return `(
+			(cars.organization_id IS NULL AND cars.owner_id = ` + userParam + `)
+			OR EXISTS (
+				SELECT 1 FROM organization_members m
+				WHERE m.organization_id = cars.organization_id AND m.user_id = ` + userParam + `
+					AND (cars.owner_id = ` + userParam + ` OR m.role = ANY(` + rolesParam + `))
+			)
+		)`
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
 type CarRepository struct {
 	db *sql.DB
 }
@@ -21,6 +89,8 @@ func (r *CarRepository) Create(car *carwise.Car) error {
 		INSERT INTO cars (
 			id, 
 			owner_id, 
+			organization_id, 
+			stock_number, 
 			title, 
 			description, 
 			currency, 
@@ -55,9 +125,11 @@ func (r *CarRepository) Create(car *carwise.Car) error {
 			front_left_door, 
 			rear_left_door, 
 			rear_left_mudguard, 
-			rear_bumper
+			rear_bumper, 
+			status, 
+			expires_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37
+			$1, $2, NULLIF($40, ''), NULLIF($41, ''), $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39
 		)`
 	_, err := r.db.Exec(query,
 		car.ID,
@@ -97,6 +169,10 @@ func (r *CarRepository) Create(car *carwise.Car) error {
 		car.RearLeftDoor,
 		car.RearLeftMudguard,
 		car.RearBumper,
+		car.Status,
+		car.ExpiresAt,
+		car.OrganizationID,
+		car.StockNumber,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create car: %w", err)
@@ -108,11 +184,14 @@ func (r *CarRepository) GetCars(page, limit, brand_id, series_id, model_id int)
 	offset := (page - 1) * limit
 
 	query := `
-		SELECT *
+		SELECT ` + carColumns + `
 		FROM cars
 	`
-	conditions := []string{}
-	args := []interface{}{}
+	conditions := []string{"owner_id IN (SELECT id FROM users WHERE status <> $1 AND deletion_requested_at IS NULL)"}
+	args := []interface{}{carwise.AccountStatusBanned}
+
+	conditions = append(conditions, "status = $"+fmt.Sprint(len(args)+1))
+	args = append(args, carwise.CarStatusActive)
 
 	if brand_id != 0 {
 		conditions = append(conditions, "brand_id = $"+fmt.Sprint(len(args)+1))
@@ -146,46 +225,64 @@ func (r *CarRepository) GetCars(page, limit, brand_id, series_id, model_id int)
 
 	var cars []carwise.Car
 	for rows.Next() {
-		var car carwise.Car
-		if err := rows.Scan(
-			&car.ID,
-			&car.OwnerId,
-			&car.Title,
-			&car.Description,
-			&car.Currency,
-			&car.Price,
-			&car.City,
-			&car.District,
-			&car.Neighborhood,
-			&car.ListingNumber,
-			&car.ListingDate,
-			&car.BrandId,
-			&car.SeriesId,
-			&car.ModelId,
-			&car.Year,
-			&car.FuelType,
-			&car.Transmission,
-			&car.Mileage,
-			&car.BodyType,
-			&car.EnginePower,
-			&car.EngineVolume,
-			&car.DriveType,
-			&car.Color,
-			&car.Warranty,
-			&car.HeavyDamage,
-			&car.SellerType,
-			&car.TradeOption,
-			&car.FrontBumper,
-			&car.FrontHood,
-			&car.Roof,
-			&car.FrontRightDoor,
-			&car.RearRightDoor,
-			&car.FrontLeftMudguard,
-			&car.FrontLeftDoor,
-			&car.RearLeftDoor,
-			&car.RearLeftMudguard,
-			&car.RearBumper,
-		); err != nil {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) GetListedBetween(brandID, seriesID, modelID int, since, until time.Time, limit int) ([]carwise.Car, error) {
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+	`
+	conditions := []string{"owner_id IN (SELECT id FROM users WHERE status <> $1 AND deletion_requested_at IS NULL)"}
+	args := []interface{}{carwise.AccountStatusBanned}
+
+	conditions = append(conditions, "status = $"+fmt.Sprint(len(args)+1))
+	args = append(args, carwise.CarStatusActive)
+
+	conditions = append(conditions, "listing_date > $"+fmt.Sprint(len(args)+1))
+	args = append(args, since)
+
+	conditions = append(conditions, "listing_date <= $"+fmt.Sprint(len(args)+1))
+	args = append(args, until)
+
+	if brandID != 0 {
+		conditions = append(conditions, "brand_id = $"+fmt.Sprint(len(args)+1))
+		args = append(args, brandID)
+	}
+	if seriesID != 0 {
+		conditions = append(conditions, "series_id = $"+fmt.Sprint(len(args)+1))
+		args = append(args, seriesID)
+	}
+	if modelID != 0 {
+		conditions = append(conditions, "model_id = $"+fmt.Sprint(len(args)+1))
+		args = append(args, modelID)
+	}
+
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	query += " ORDER BY listing_date DESC LIMIT $" + fmt.Sprint(len(args)+1)
+	args = append(args, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan car: %w", err)
 		}
 		cars = append(cars, car)
@@ -200,16 +297,421 @@ func (r *CarRepository) GetCars(page, limit, brand_id, series_id, model_id int)
 
 func (r *CarRepository) GetByID(id string) (*carwise.Car, error) {
 	query := `
-		SELECT *
+		SELECT ` + carColumns + `
 		FROM cars
 		WHERE id = $1
 	`
-	row := r.db.QueryRow(query, id)
+	car, err := scanCar(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("car not found : %w", err)
+		}
+		return nil, fmt.Errorf("failed to fetch car: %w", err)
+	}
+
+	return &car, nil
+}
+
+func (r *CarRepository) GetByIDs(ids []string) ([]carwise.Car, error) {
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE id = ANY($1)
+	`
+	rows, err := r.db.Query(query, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) GetByOwner(ownerID string) ([]carwise.Car, error) {
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE owner_id = $1
+		ORDER BY listing_date DESC
+	`
+	rows, err := r.db.Query(query, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) GetByStockNumber(organizationID, stockNumber string) (*carwise.Car, error) {
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE organization_id = $1 AND stock_number = $2
+	`
+	car, err := scanCar(r.db.QueryRow(query, organizationID, stockNumber))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch car: %w", err)
+	}
+
+	return &car, nil
+}
+
+func (r *CarRepository) UpdateListing(car *carwise.Car) error {
+	query := `
+		UPDATE cars 
+		SET 
+			title = $1, 
+			description = $2, 
+			currency = $3, 
+			price = $4, 
+			city = $5, 
+			district = $6, 
+			neighborhood = $7, 
+			brand_id = $8, 
+			series_id = $9, 
+			model_id = $10, 
+			year = $11, 
+			fuel_type = $12, 
+			transmission = $13, 
+			mileage = $14, 
+			body_type = $15, 
+			engine_power = $16, 
+			engine_volume = $17, 
+			drive_type = $18, 
+			color = $19, 
+			warranty = $20, 
+			heavy_damage = $21, 
+			seller_type = $22, 
+			trade_option = $23, 
+			front_bumper = $24, 
+			front_hood = $25, 
+			roof = $26, 
+			front_right_door = $27, 
+			rear_right_door = $28, 
+			front_left_mudguard = $29, 
+			front_left_door = $30, 
+			rear_left_door = $31, 
+			rear_left_mudguard = $32, 
+			rear_bumper = $33 
+		WHERE id = $34`
+
+	_, err := r.db.Exec(query,
+		car.Title,
+		car.Description,
+		car.Currency,
+		car.Price,
+		car.City,
+		car.District,
+		car.Neighborhood,
+		car.BrandId,
+		car.SeriesId,
+		car.ModelId,
+		car.Year,
+		car.FuelType,
+		car.Transmission,
+		car.Mileage,
+		car.BodyType,
+		car.EnginePower,
+		car.EngineVolume,
+		car.DriveType,
+		car.Color,
+		car.Warranty,
+		car.HeavyDamage,
+		car.SellerType,
+		car.TradeOption,
+		car.FrontBumper,
+		car.FrontHood,
+		car.Roof,
+		car.FrontRightDoor,
+		car.RearRightDoor,
+		car.FrontLeftMudguard,
+		car.FrontLeftDoor,
+		car.RearLeftDoor,
+		car.RearLeftMudguard,
+		car.RearBumper,
+		car.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update car: %w", err)
+	}
+	return nil
+}
+
+func (r *CarRepository) ExpireMissingStock(organizationID string, stockNumbers []string) (int64, error) {
+	query := `
+		UPDATE cars 
+		SET status = $3 
+		WHERE organization_id = $1 AND stock_number IS NOT NULL AND NOT (stock_number = ANY($2)) AND status = ANY($4)`
+
+	result, err := r.db.Exec(query, organizationID, pq.Array(stockNumbers), carwise.CarStatusExpired, pq.Array([]string{carwise.CarStatusActive, carwise.CarStatusPaused}))
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire missing stock: %w", err)
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return expired, nil
+}
+
+func (r *CarRepository) GetManagedBy(userID string) ([]carwise.Car, error) {
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE ` + managedByCondition("$1", "$2") + `
+		ORDER BY listing_date DESC
+	`
+	rows, err := r.db.Query(query, userID, pq.Array(managerRoles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) GetActiveByOrganization(organizationID string, page, limit int) ([]carwise.Car, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE organization_id = $1 AND status = $2
+		ORDER BY listing_date DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(query, organizationID, carwise.CarStatusActive, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) GetActiveByOwner(ownerID string, page, limit int) ([]carwise.Car, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE owner_id = $1 AND status = $2
+		ORDER BY listing_date DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(query, ownerID, carwise.CarStatusActive, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) GetSellerStats(ownerID string) (*carwise.SellerStats, error) {
+	query := `
+		SELECT 
+			COUNT(*) FILTER (WHERE status = $2), 
+			COUNT(*) FILTER (WHERE status = $3), 
+			COALESCE(BOOL_OR(seller_type = $4), FALSE) 
+		FROM cars
+		WHERE owner_id = $1`
+
+	stats := &carwise.SellerStats{}
+	err := r.db.QueryRow(query, ownerID, carwise.CarStatusActive, carwise.CarStatusSold, carwise.SellerTypeDealer).Scan(
+		&stats.ActiveListings,
+		&stats.SoldListings,
+		&stats.HasDealerListings,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch seller stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (r *CarRepository) IncrementViewCount(id string) error {
+	_, err := r.db.Exec(`UPDATE cars SET view_count = view_count + 1 WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to increment view count: %w", err)
+	}
+	return nil
+}
+
+func (r *CarRepository) UpdateStatus(userID string, ids []string, fromStatuses []string, status string, expiresAt time.Time) ([]string, error) {
+	query := `
+		UPDATE cars 
+		SET 
+			status = $1, 
+			expires_at = COALESCE($2, expires_at), 
+			expiry_notified_at = CASE WHEN $2 IS NULL THEN expiry_notified_at END 
+		WHERE ` + managedByCondition("$3", "$6") + ` AND id = ANY($4) AND status = ANY($5) 
+		RETURNING id`
+
+	rows, err := r.db.Query(query, status, sql.NullTime{Time: expiresAt, Valid: !expiresAt.IsZero()}, userID, pq.Array(ids), pq.Array(fromStatuses), pq.Array(managerRoles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update car status: %w", err)
+	}
+	defer rows.Close()
+
+	var updated []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan car id: %w", err)
+		}
+		updated = append(updated, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return updated, nil
+}
+
+func (r *CarRepository) SetOrganizationSellerType(organizationID, sellerType string) error {
+	_, err := r.db.Exec(`UPDATE cars SET seller_type = $1 WHERE organization_id = $2`, sellerType, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to update seller type: %w", err)
+	}
+	return nil
+}
+
+func (r *CarRepository) SetStatus(id, status string) error {
+	_, err := r.db.Exec(`UPDATE cars SET status = $1 WHERE id = $2`, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update car status: %w", err)
+	}
+	return nil
+}
+
+func (r *CarRepository) GetExpiring(before time.Time) ([]carwise.Car, error) {
+	query := `
+		SELECT ` + carColumns + `
+		FROM cars
+		WHERE status = $1 AND expires_at <= $2 AND expiry_notified_at IS NULL
+		ORDER BY expires_at
+	`
+	rows, err := r.db.Query(query, carwise.CarStatusActive, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cars: %w", err)
+	}
+	defer rows.Close()
+
+	var cars []carwise.Car
+	for rows.Next() {
+		car, err := scanCar(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan car: %w", err)
+		}
+		cars = append(cars, car)
+	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return cars, nil
+}
+
+func (r *CarRepository) MarkExpiryNotified(id string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE cars SET expiry_notified_at = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to update car: %w", err)
+	}
+	return nil
+}
+
+func (r *CarRepository) ExpireListings(before time.Time) (int64, error) {
+	result, err := r.db.Exec(`UPDATE cars SET status = $1 WHERE status = $2 AND expires_at <= $3`, carwise.CarStatusExpired, carwise.CarStatusActive, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire listings: %w", err)
+	}
+
+	expired, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return expired, nil
+}
+
+func scanCar(row rowScanner) (carwise.Car, error) {
 	var car carwise.Car
 	err := row.Scan(
 		&car.ID,
 		&car.OwnerId,
+		&car.OrganizationID,
+		&car.StockNumber,
 		&car.Title,
 		&car.Description,
 		&car.Currency,
@@ -245,13 +747,9 @@ func (r *CarRepository) GetByID(id string) (*carwise.Car, error) {
 		&car.RearLeftDoor,
 		&car.RearLeftMudguard,
 		&car.RearBumper,
+		&car.Status,
+		&car.ExpiresAt,
+		&car.ViewCount,
 	)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("car not found : %w", err)
-		}
-		return nil, fmt.Errorf("failed to fetch car: %w", err)
-	}
-
-	return &car, nil
+	return car, err
 }
@@ -0,0 +1,78 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type ContactRevealRepository struct {
+	db *sql.DB
+}
+
+func NewContactRevealRepository() *ContactRevealRepository {
+	database := ConnectDb()
+	return &ContactRevealRepository{db: database}
+}
+
+func (r *ContactRevealRepository) Create(reveal *carwise.ContactReveal) error {
+	query := `
+		INSERT INTO contact_reveals (
+			id, 
+			car_id, 
+			seller_id, 
+			viewer_id, 
+			ip, 
+			masked, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`
+	_, err := r.db.Exec(query,
+		reveal.ID,
+		reveal.CarID,
+		reveal.SellerID,
+		reveal.ViewerID,
+		reveal.IP,
+		reveal.Masked,
+		reveal.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create contact reveal: %w", err)
+	}
+	return nil
+}
+
+func (r *ContactRevealRepository) CountByCars(carIDs []string) (map[string]int, error) {
+	query := `
+		SELECT 
+			car_id, 
+			COUNT(*) 
+		FROM contact_reveals 
+		WHERE car_id = ANY($1) 
+		GROUP BY car_id`
+
+	rows, err := r.db.Query(query, pq.Array(carIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count contact reveals: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var carID string
+		var count int
+		if err := rows.Scan(&carID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan contact reveal count: %w", err)
+		}
+		counts[carID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return counts, nil
+}
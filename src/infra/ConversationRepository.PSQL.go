@@ -0,0 +1,420 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const conversationColumns = `
+			c.id, 
+			c.car_id, 
+			c.seller_id, 
+			c.buyer_id, 
+			c.last_message_at, 
+			c.created_at`
+
+const messageColumns = `
+			m.id, 
+			m.conversation_id, 
+			m.sender_id, 
+			m.body, 
+			m.attachments, 
+			m.read_at, 
+			m.notified_at, 
+			m.created_at`
+
+type ConversationRepository struct {
+	db *sql.DB
+}
+
+func NewConversationRepository() *ConversationRepository {
+	database := ConnectDb()
+	return &ConversationRepository{db: database}
+}
+
+func (r *ConversationRepository) Create(conversation *carwise.Conversation) error {
+	query := `
+		INSERT INTO conversations (
+			id, 
+			car_id, 
+			seller_id, 
+			buyer_id, 
+			last_message_at, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)`
+	_, err := r.db.Exec(query,
+		conversation.ID,
+		conversation.CarID,
+		conversation.SellerID,
+		conversation.BuyerID,
+		conversation.LastMessageAt,
+		conversation.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create conversation: %w", err)
+	}
+	return nil
+}
+
+func (r *ConversationRepository) GetByID(id string) (*carwise.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		WHERE c.id = $1
+	`
+	conversation, err := scanConversation(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch conversation: %w", err)
+	}
+	return &conversation, nil
+}
+
+func (r *ConversationRepository) GetByCarAndBuyer(carID, buyerID string) (*carwise.Conversation, error) {
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		WHERE c.car_id = $1 AND c.buyer_id = $2
+	`
+	conversation, err := scanConversation(r.db.QueryRow(query, carID, buyerID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch conversation: %w", err)
+	}
+	return &conversation, nil
+}
+
+func (r *ConversationRepository) GetByUser(userID string, page, limit int) ([]carwise.Conversation, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + conversationColumns + `
+		FROM conversations c
+		WHERE c.seller_id = $1 OR c.buyer_id = $1
+		ORDER BY c.last_message_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var conversations []carwise.Conversation
+	for rows.Next() {
+		conversation, err := scanConversation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		conversations = append(conversations, conversation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return conversations, nil
+}
+
+func (r *ConversationRepository) GetIDsByUser(userID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT id FROM conversations WHERE seller_id = $1 OR buyer_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan conversation: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *ConversationRepository) CreateMessage(message *carwise.Message) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO messages (
+			id, 
+			conversation_id, 
+			sender_id, 
+			body, 
+			attachments, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)`
+	_, err = tx.Exec(query,
+		message.ID,
+		message.ConversationID,
+		message.SenderID,
+		message.Body,
+		pq.Array(message.Attachments),
+		message.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create message: %w", err)
+	}
+
+	_, err = tx.Exec(`UPDATE conversations SET last_message_at = $1 WHERE id = $2`, message.CreatedAt, message.ConversationID)
+	if err != nil {
+		return fmt.Errorf("failed to update conversation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ConversationRepository) GetMessages(conversationID string, page, limit int) ([]carwise.Message, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + messageColumns + `
+		FROM messages m
+		WHERE m.conversation_id = $1
+		ORDER BY m.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	return r.queryMessages(query, conversationID, limit, offset)
+}
+
+func (r *ConversationRepository) GetLastMessages(conversationIDs []string) (map[string]carwise.Message, error) {
+	query := `
+		SELECT DISTINCT ON (m.conversation_id) ` + messageColumns + `
+		FROM messages m
+		WHERE m.conversation_id = ANY($1)
+		ORDER BY m.conversation_id, m.created_at DESC
+	`
+	messages, err := r.queryMessages(query, pq.Array(conversationIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	lastMessages := make(map[string]carwise.Message)
+	for _, message := range messages {
+		lastMessages[message.ConversationID] = message
+	}
+	return lastMessages, nil
+}
+
+func (r *ConversationRepository) MarkRead(conversationID, userID string, at time.Time) error {
+	query := `
+		UPDATE messages 
+		SET read_at = $1 
+		WHERE conversation_id = $2 AND sender_id <> $3 AND read_at IS NULL`
+	_, err := r.db.Exec(query, at, conversationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark messages as read: %w", err)
+	}
+	return nil
+}
+
+func (r *ConversationRepository) CountUnread(userID string, conversationIDs []string) (map[string]int, error) {
+	query := `
+		SELECT 
+			conversation_id, 
+			COUNT(*) 
+		FROM messages 
+		WHERE conversation_id = ANY($1) AND sender_id <> $2 AND read_at IS NULL 
+		GROUP BY conversation_id`
+
+	rows, err := r.db.Query(query, pq.Array(conversationIDs), userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var conversationID string
+		var count int
+		if err := rows.Scan(&conversationID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan unread count: %w", err)
+		}
+		counts[conversationID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *ConversationRepository) CountAllUnread(userID string) (int, error) {
+	query := `
+		SELECT COUNT(*) 
+		FROM messages m 
+		JOIN conversations c ON c.id = m.conversation_id 
+		WHERE (c.seller_id = $1 OR c.buyer_id = $1) AND m.sender_id <> $1 AND m.read_at IS NULL`
+
+	var count int
+	err := r.db.QueryRow(query, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread messages: %w", err)
+	}
+	return count, nil
+}
+
+// ClaimUnnotified marks the unread messages created before the given time as
+// notified and returns them. Rows locked by another instance are skipped, so
+// each message is only picked up once.
+func (r *ConversationRepository) ClaimUnnotified(before, at time.Time) ([]carwise.Message, error) {
+	query := `
+		WITH claimed AS (
+			UPDATE messages 
+			SET notified_at = $2 
+			WHERE id IN (
+				SELECT id FROM messages
+				WHERE read_at IS NULL AND notified_at IS NULL AND created_at <= $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING *
+		)
+		SELECT ` + messageColumns + `
+		FROM claimed m
+		ORDER BY m.created_at
+	`
+	return r.queryMessages(query, before, at)
+}
+
+// ReleaseNotified clears the notified mark of messages whose notification
+// could not be sent, so the next run picks them up again.
+func (r *ConversationRepository) ReleaseNotified(ids []string) error {
+	_, err := r.db.Exec(`UPDATE messages SET notified_at = NULL WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to release message notifications: %w", err)
+	}
+	return nil
+}
+
+func (r *ConversationRepository) CountByCars(carIDs []string) (map[string]int, error) {
+	query := `
+		SELECT 
+			c.car_id, 
+			COUNT(m.id) 
+		FROM conversations c 
+		JOIN messages m ON m.conversation_id = c.id 
+		WHERE c.car_id = ANY($1) 
+		GROUP BY c.car_id`
+
+	rows, err := r.db.Query(query, pq.Array(carIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var carID string
+		var count int
+		if err := rows.Scan(&carID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan message count: %w", err)
+		}
+		counts[carID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *ConversationRepository) GetResponseStats(sellerID string) (*carwise.ResponseStats, error) {
+	query := `
+		SELECT 
+			COUNT(*), 
+			COUNT(*) FILTER (WHERE EXISTS (
+				SELECT 1 FROM messages m WHERE m.conversation_id = c.id AND m.sender_id = c.seller_id
+			)) 
+		FROM conversations c 
+		WHERE c.seller_id = $1`
+
+	stats := &carwise.ResponseStats{}
+	err := r.db.QueryRow(query, sellerID).Scan(&stats.Conversations, &stats.Responded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch response stats: %w", err)
+	}
+	return stats, nil
+}
+
+func (r *ConversationRepository) queryMessages(query string, args ...interface{}) ([]carwise.Message, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []carwise.Message
+	for rows.Next() {
+		message, err := scanMessage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return messages, nil
+}
+
+func scanConversation(row rowScanner) (carwise.Conversation, error) {
+	var conversation carwise.Conversation
+	err := row.Scan(
+		&conversation.ID,
+		&conversation.CarID,
+		&conversation.SellerID,
+		&conversation.BuyerID,
+		&conversation.LastMessageAt,
+		&conversation.CreatedAt,
+	)
+	return conversation, err
+}
+
+func scanMessage(row rowScanner) (carwise.Message, error) {
+	var message carwise.Message
+	var readAt, notifiedAt sql.NullTime
+	err := row.Scan(
+		&message.ID,
+		&message.ConversationID,
+		&message.SenderID,
+		&message.Body,
+		pq.Array(&message.Attachments),
+		&readAt,
+		&notifiedAt,
+		&message.CreatedAt,
+	)
+	message.ReadAt = readAt.Time
+	message.NotifiedAt = notifiedAt.Time
+	return message, err
+}
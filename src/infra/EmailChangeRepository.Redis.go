@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"carwise"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type EmailChangeRepository struct {
+	client *redis.Client
+}
+
+func NewEmailChangeRepository() *EmailChangeRepository {
+	return &EmailChangeRepository{client: ConnectRedis()}
+}
+
+func (r *EmailChangeRepository) SaveEmailChange(userID string, change *carwise.EmailChange, ttl time.Duration) error {
+	key := fmt.Sprintf("email-change:%s", userID)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "new_email", change.NewEmail, "code_hash", change.CodeHash)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save email change to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *EmailChangeRepository) GetEmailChange(userID string) (*carwise.EmailChange, error) {
+	key := fmt.Sprintf("email-change:%s", userID)
+	values, err := r.client.HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve email change from Redis: %v", err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return &carwise.EmailChange{
+		NewEmail: values["new_email"],
+		CodeHash: values["code_hash"],
+	}, nil
+}
+
+func (r *EmailChangeRepository) DeleteEmailChange(userID string) error {
+	key := fmt.Sprintf("email-change:%s", userID)
+	err := r.client.Del(context.Background(), key).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete email change from Redis: %v", err)
+	}
+
+	return nil
+}
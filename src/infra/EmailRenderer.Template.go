@@ -0,0 +1,180 @@
+package infra
+
+import (
+	"bytes"
+	"carwise"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/email
+var emailTemplateFS embed.FS
+
+const emailTemplateDir = "templates/email"
+
+var emailDateLayouts = map[string]string{
+	carwise.LocaleEnglish: "January 2, 2006",
+	carwise.LocaleTurkish: "02.01.2006",
+}
+
+// TemplateEmailRenderer renders the embedded email templates. Every message
+// has a .txt file defining its "subject" and plain text "content" blocks and
+// a .html file defining its HTML "content" block; both are wrapped in the
+// matching layout. Copy lives in the per-locale catalogs and is looked up
+// with the "t" template function.
+type TemplateEmailRenderer struct {
+	html     map[string]*htmltemplate.Template
+	text     map[string]*texttemplate.Template
+	catalogs map[string]map[string]string
+}
+
+func NewEmailRenderer() *TemplateEmailRenderer {
+	renderer := &TemplateEmailRenderer{
+		html:     map[string]*htmltemplate.Template{},
+		text:     map[string]*texttemplate.Template{},
+		catalogs: map[string]map[string]string{},
+	}
+
+	for _, locale := range carwise.Locales {
+		content, err := emailTemplateFS.ReadFile(path.Join(emailTemplateDir, "locales", locale+".json"))
+		if err != nil {
+			log.Fatal("Failed to read email locale: ", err)
+		}
+		catalog := map[string]string{}
+		if err := json.Unmarshal(content, &catalog); err != nil {
+			log.Fatal("Failed to parse email locale: ", err)
+		}
+		renderer.catalogs[locale] = catalog
+	}
+
+	files, err := fs.Glob(emailTemplateFS, path.Join(emailTemplateDir, "*.txt"))
+	if err != nil {
+		log.Fatal("Failed to list email templates: ", err)
+	}
+	funcs := renderer.funcs(carwise.DefaultLocale)
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".txt")
+		if name == "layout" {
+			continue
+		}
+		renderer.text[name] = texttemplate.Must(texttemplate.New("layout.txt").
+			Funcs(texttemplate.FuncMap(funcs)).
+			ParseFS(emailTemplateFS, path.Join(emailTemplateDir, "layout.txt"), file))
+		renderer.html[name] = htmltemplate.Must(htmltemplate.New("layout.html").
+			Funcs(htmltemplate.FuncMap(funcs)).
+			ParseFS(emailTemplateFS, path.Join(emailTemplateDir, "layout.html"), path.Join(emailTemplateDir, name+".html")))
+	}
+
+	return renderer
+}
+
+func (r *TemplateEmailRenderer) Templates() []string {
+	names := []string{}
+	for name := range r.text {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *TemplateEmailRenderer) Render(name, locale string, data map[string]interface{}) (*carwise.Email, error) {
+	textTemplate, ok := r.text[name]
+	if !ok {
+		return nil, carwise.ErrEmailTemplateNotFound
+	}
+	if _, ok := r.catalogs[locale]; !ok {
+		locale = carwise.DefaultLocale
+	}
+
+	values := map[string]interface{}{}
+	for key, value := range data {
+		values[key] = value
+	}
+	values["Locale"] = locale
+
+	// The parsed templates are shared between requests, so the
+	// locale-bound functions are installed on a fresh clone every time.
+	funcs := r.funcs(locale)
+	textTemplate, err := textTemplate.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone email template: %w", err)
+	}
+	textTemplate.Funcs(texttemplate.FuncMap(funcs))
+	htmlTemplate, err := r.html[name].Clone()
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone email template: %w", err)
+	}
+	htmlTemplate.Funcs(htmltemplate.FuncMap(funcs))
+
+	var subject, text, html bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return nil, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	values["Subject"] = strings.TrimSpace(subject.String())
+
+	if err := textTemplate.ExecuteTemplate(&text, "layout.txt", values); err != nil {
+		return nil, fmt.Errorf("failed to render email text: %w", err)
+	}
+	if err := htmlTemplate.ExecuteTemplate(&html, "layout.html", values); err != nil {
+		return nil, fmt.Errorf("failed to render email html: %w", err)
+	}
+
+	return &carwise.Email{
+		Subject: values["Subject"].(string),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// Translate formats a catalog entry outside of an email, e.g. for in-app
+// notifications. Dates among the arguments use the locale's date layout.
+func (r *TemplateEmailRenderer) Translate(locale, key string, args ...interface{}) string {
+	if _, ok := r.catalogs[locale]; !ok {
+		locale = carwise.DefaultLocale
+	}
+	values := make([]interface{}, len(args))
+	for idx, arg := range args {
+		values[idx] = arg
+		if t, ok := arg.(time.Time); ok {
+			values[idx] = t.Format(emailDateLayouts[locale])
+		}
+	}
+	return r.translate(locale, key, values...)
+}
+
+func (r *TemplateEmailRenderer) translate(locale, key string, args ...interface{}) string {
+	format, ok := r.catalogs[locale][key]
+	if !ok {
+		format, ok = r.catalogs[carwise.DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+func (r *TemplateEmailRenderer) funcs(locale string) map[string]interface{} {
+	return map[string]interface{}{
+		"t": func(key string, args ...interface{}) string {
+			return r.translate(locale, key, args...)
+		},
+		"date": func(t time.Time) string {
+			return t.Format(emailDateLayouts[locale])
+		},
+		"action": func(url, label string) map[string]string {
+			return map[string]string{"URL": url, "Label": label}
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package infra
+
+import (
+	"carwise"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+var offerEmailEvents = []string{
+	"received",
+	"accepted",
+	"counter_accepted",
+	"declined",
+	"counter_declined",
+	"countered",
+	"expired",
+	"closed",
+}
+
+func emailTestData(event string) map[string]interface{} {
+	return map[string]interface{}{
+		"Name":            "Jane",
+		"Link":            "https://carwise.example/link",
+		"Minutes":         15,
+		"Code":            "482913",
+		"NewEmail":        "jane.new@example.com",
+		"DeletionDate":    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		"ExpiresAt":       time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+		"Title":           "2019 Volkswagen Golf 1.5 TSI Highline",
+		"OldPrice":        "18500.00 EUR",
+		"NewPrice":        "17250.00 EUR",
+		"SearchName":      "Golf under 20k",
+		"UnsubscribeLink": "https://carwise.example/unsubscribe",
+		"Listings": []map[string]interface{}{{
+			"Year":   2019,
+			"Brand":  "Volkswagen",
+			"Series": "Golf",
+			"Model":  "1.5 TSI",
+			"Title":  "Golf Highline",
+			"Price":  "17250.00 EUR",
+			"Link":   "https://carwise.example/cars/1",
+		}},
+		"Sender":       "John Doe",
+		"Count":        2,
+		"Event":        event,
+		"Counterpart":  "John Doe",
+		"Amount":       "17000.00 EUR",
+		"TradeIn":      true,
+		"Reason":       "The photos do not match the vehicle.",
+		"Inviter":      "John Doe",
+		"Organization": "Carwise Motors",
+		"Role":         carwise.OrganizationRoleManager,
+		"Days":         7,
+	}
+}
+
+func TestEmailRendererTemplates(t *testing.T) {
+	want := []string{
+		carwise.EmailTemplateVerifyEmail,
+		carwise.EmailTemplateAccountLocked,
+		carwise.EmailTemplatePasswordReset,
+		carwise.EmailTemplateEmailChangeConfirm,
+		carwise.EmailTemplateEmailChangeNotice,
+		carwise.EmailTemplateAccountDeletion,
+		carwise.EmailTemplateAccountDeletionCode,
+		carwise.EmailTemplatePriceDrop,
+		carwise.EmailTemplateSearchAlert,
+		carwise.EmailTemplateUnreadMessages,
+		carwise.EmailTemplateOffer,
+		carwise.EmailTemplateListingApproved,
+		carwise.EmailTemplateListingRejected,
+		carwise.EmailTemplateListingExpiring,
+		carwise.EmailTemplateOrganizationInvitation,
+	}
+	sort.Strings(want)
+
+	if got := NewEmailRenderer().Templates(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got templates %v, want %v", got, want)
+	}
+}
+
+func TestEmailRendererCatalogs(t *testing.T) {
+	renderer := NewEmailRenderer()
+	reference := renderer.catalogs[carwise.DefaultLocale]
+
+	for _, locale := range carwise.Locales {
+		t.Run(locale, func(t *testing.T) {
+			catalog := renderer.catalogs[locale]
+			for key, value := range catalog {
+				if _, ok := reference[key]; !ok {
+					t.Errorf("key %q is not in the %s catalog", key, carwise.DefaultLocale)
+				}
+				if strings.TrimSpace(value) == "" {
+					t.Errorf("key %q is empty", key)
+				}
+			}
+			for key := range reference {
+				if _, ok := catalog[key]; !ok {
+					t.Errorf("key %q is missing", key)
+				}
+			}
+		})
+	}
+}
+
+func TestEmailRendererRender(t *testing.T) {
+	renderer := NewEmailRenderer()
+
+	type renderCase struct {
+		name     string
+		template string
+		event    string
+	}
+	var cases []renderCase
+	for _, name := range renderer.Templates() {
+		if name == carwise.EmailTemplateOffer {
+			for _, event := range offerEmailEvents {
+				cases = append(cases, renderCase{name: name + "/" + event, template: name, event: event})
+			}
+			continue
+		}
+		cases = append(cases, renderCase{name: name, template: name})
+	}
+
+	for _, locale := range carwise.Locales {
+		for _, tt := range cases {
+			t.Run(locale+"/"+tt.name, func(t *testing.T) {
+				email, err := renderer.Render(tt.template, locale, emailTestData(tt.event))
+				if err != nil {
+					t.Fatalf("Render: %v", err)
+				}
+
+				if email.Subject == "" || strings.ContainsAny(email.Subject, "\r\n") {
+					t.Errorf("invalid subject %q", email.Subject)
+				}
+				if !strings.Contains(email.HTML, `lang="`+locale+`"`) {
+					t.Errorf("html is not marked as %s", locale)
+				}
+				if !strings.Contains(email.Text, "Jane") || !strings.Contains(email.HTML, "Jane") {
+					t.Error("email does not greet the recipient")
+				}
+				for part, content := range map[string]string{"subject": email.Subject, "text": email.Text, "html": email.HTML} {
+					if strings.Contains(content, "%!") {
+						t.Errorf("%s has a formatting error: %q", part, content)
+					}
+					for key := range renderer.catalogs[locale] {
+						if strings.Contains(key, ".") && strings.Contains(content, key) {
+							t.Errorf("%s contains the untranslated key %q", part, key)
+						}
+					}
+				}
+			})
+		}
+	}
+
+	if _, err := renderer.Render("missing", carwise.DefaultLocale, nil); err != carwise.ErrEmailTemplateNotFound {
+		t.Errorf("got error %v for a missing template, want %v", err, carwise.ErrEmailTemplateNotFound)
+	}
+}
+
+func TestEmailRendererTranslate(t *testing.T) {
+	renderer := NewEmailRenderer()
+	expiresAt := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		args   []interface{}
+		want   string
+	}{
+		{name: "english date", locale: carwise.LocaleEnglish, key: "notification.listing_expiring.body", args: []interface{}{"Golf", expiresAt}, want: "Your listing \"Golf\" expires on March 4, 2026. Renew it to keep it visible."},
+		{name: "turkish date", locale: carwise.LocaleTurkish, key: "notification.listing_expiring.body", args: []interface{}{"Golf", expiresAt}, want: "\"Golf\" ilanınızın süresi 04.03.2026 tarihinde doluyor. Görünür kalması için ilanınızı yenileyin."},
+		{name: "reordered arguments", locale: carwise.LocaleTurkish, key: "notification.price_drop.body", args: []interface{}{"Golf", "17250.00 EUR", "18500.00 EUR"}, want: "Golf ilanının fiyatı 18500.00 EUR yerine artık 17250.00 EUR."},
+		{name: "unknown locale", locale: "de", key: "notification.message.title", want: "New Message"},
+		{name: "missing key", locale: carwise.LocaleEnglish, key: "notification.missing.title", want: "notification.missing.title"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderer.Translate(tt.locale, tt.key, tt.args...); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
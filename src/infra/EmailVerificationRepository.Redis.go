@@ -0,0 +1,68 @@
+package infra
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type EmailVerificationRepository struct {
+	client *redis.Client
+}
+
+func NewEmailVerificationRepository() *EmailVerificationRepository {
+	return &EmailVerificationRepository{client: ConnectRedis()}
+}
+
+func (r *EmailVerificationRepository) SaveVerificationCode(email, codeHash string, ttl time.Duration) error {
+	key := fmt.Sprintf("email-verification:%s", email)
+	err := r.client.Set(context.Background(), key, codeHash, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("failed to save email verification code to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *EmailVerificationRepository) VerifyVerificationCode(email, codeHash string) (bool, error) {
+	key := fmt.Sprintf("email-verification:%s", email)
+	storedHash, err := r.client.Get(context.Background(), key).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve email verification code from Redis: %v", err)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(storedHash), []byte(codeHash)) == 1, nil
+}
+
+func (r *EmailVerificationRepository) DeleteVerificationCode(email string) error {
+	key := fmt.Sprintf("email-verification:%s", email)
+	err := r.client.Del(context.Background(), key).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete email verification code from Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *EmailVerificationRepository) IncrementResendCount(email string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("email-verification-resend:%s", email)
+	count, err := r.client.Incr(context.Background(), key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment email verification resend count in Redis: %v", err)
+	}
+
+	if count == 1 {
+		err = r.client.Expire(context.Background(), key, window).Err()
+		if err != nil {
+			return 0, fmt.Errorf("failed to set email verification resend window in Redis: %v", err)
+		}
+	}
+
+	return count, nil
+}
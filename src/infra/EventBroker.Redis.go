@@ -0,0 +1,101 @@
+package infra
+
+import (
+	"carwise"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	eventChannel    = "carwise:events"
+	eventBufferSize = 16
+)
+
+// EventBroker fans events out to every API instance through a single Redis
+// pub/sub channel; each instance then delivers them to its local subscribers.
+type EventBroker struct {
+	client      *redis.Client
+	mu          sync.Mutex
+	subscribers map[string]map[chan carwise.Event]struct{}
+}
+
+func NewEventBroker() *EventBroker {
+	broker := &EventBroker{
+		client:      ConnectRedis(),
+		subscribers: make(map[string]map[chan carwise.Event]struct{}),
+	}
+	go broker.listen()
+	return broker
+}
+
+func (b *EventBroker) Publish(event *carwise.Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	err = b.client.Publish(context.Background(), eventChannel, payload).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish event to Redis: %w", err)
+	}
+	return nil
+}
+
+func (b *EventBroker) Subscribe(userID string) (<-chan carwise.Event, func()) {
+	events := make(chan carwise.Event, eventBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan carwise.Event]struct{})
+	}
+	b.subscribers[userID][events] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[userID], events)
+			if len(b.subscribers[userID]) == 0 {
+				delete(b.subscribers, userID)
+			}
+			close(events)
+		})
+	}
+
+	return events, unsubscribe
+}
+
+func (b *EventBroker) listen() {
+	pubsub := b.client.Subscribe(context.Background(), eventChannel)
+	defer pubsub.Close()
+
+	for message := range pubsub.Channel() {
+		var event carwise.Event
+		if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+			log.Printf("failed to decode event: %v", err)
+			continue
+		}
+		b.dispatch(event)
+	}
+}
+
+func (b *EventBroker) dispatch(event carwise.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for events := range b.subscribers[event.UserID] {
+		select {
+		case events <- event:
+		default:
+			// A slow client should not hold up everyone else; it can resync
+			// through the regular endpoints.
+		}
+	}
+}
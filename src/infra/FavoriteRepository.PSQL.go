@@ -0,0 +1,156 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+type FavoriteRepository struct {
+	db *sql.DB
+}
+
+func NewFavoriteRepository() *FavoriteRepository {
+	database := ConnectDb()
+	return &FavoriteRepository{db: database}
+}
+
+func (r *FavoriteRepository) Add(favorite *carwise.Favorite) error {
+	query := `
+		INSERT INTO favorites (
+			user_id, 
+			car_id, 
+			created_at
+		) VALUES (
+			$1, $2, $3
+		) 
+		ON CONFLICT (user_id, car_id) DO NOTHING`
+	_, err := r.db.Exec(query, favorite.UserID, favorite.CarID, favorite.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add favorite: %w", err)
+	}
+	return nil
+}
+
+func (r *FavoriteRepository) Remove(userID, carID string) error {
+	_, err := r.db.Exec(`DELETE FROM favorites WHERE user_id = $1 AND car_id = $2`, userID, carID)
+	if err != nil {
+		return fmt.Errorf("failed to remove favorite: %w", err)
+	}
+	return nil
+}
+
+func (r *FavoriteRepository) GetByUser(userID string, page, limit int) ([]carwise.Favorite, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT 
+			user_id, 
+			car_id, 
+			created_at 
+		FROM favorites 
+		WHERE user_id = $1 
+		ORDER BY created_at DESC 
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch favorites: %w", err)
+	}
+	defer rows.Close()
+
+	var favorites []carwise.Favorite
+	for rows.Next() {
+		var favorite carwise.Favorite
+		if err := rows.Scan(&favorite.UserID, &favorite.CarID, &favorite.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %w", err)
+		}
+		favorites = append(favorites, favorite)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return favorites, nil
+}
+
+func (r *FavoriteRepository) GetFavoriteCarIDs(userID string, carIDs []string) (map[string]bool, error) {
+	rows, err := r.db.Query(`SELECT car_id FROM favorites WHERE user_id = $1 AND car_id = ANY($2)`, userID, pq.Array(carIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch favorites: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := make(map[string]bool)
+	for rows.Next() {
+		var carID string
+		if err := rows.Scan(&carID); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %w", err)
+		}
+		favorites[carID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return favorites, nil
+}
+
+func (r *FavoriteRepository) GetUserIDsByCar(carID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM favorites WHERE car_id = $1`, carID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch favorites: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %w", err)
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *FavoriteRepository) CountByCars(carIDs []string) (map[string]int, error) {
+	query := `
+		SELECT 
+			car_id, 
+			COUNT(*) 
+		FROM favorites 
+		WHERE car_id = ANY($1) 
+		GROUP BY car_id`
+
+	rows, err := r.db.Query(query, pq.Array(carIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count favorites: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var carID string
+		var count int
+		if err := rows.Scan(&carID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite count: %w", err)
+		}
+		counts[carID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,126 @@
+package infra
+
+import (
+	"carwise"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+type OIDCProvider struct {
+	config   oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+func NewOIDCProvider(issuer, clientID, clientSecret, redirectURL string) (*OIDCProvider, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC provider: %w", err)
+	}
+
+	return &OIDCProvider{
+		config: oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			RedirectURL:  redirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+		},
+		verifier: provider.Verifier(&oidc.Config{ClientID: clientID}),
+	}, nil
+}
+
+// NewIdentityProviders configures every provider listed in OIDC_PROVIDERS from its
+// OIDC_<NAME>_ISSUER, _CLIENT_ID, _CLIENT_SECRET and _REDIRECT_URL variables.
+func NewIdentityProviders(frontendURL string) map[string]carwise.IdentityProvider {
+	providers := map[string]carwise.IdentityProvider{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		redirectURL := strings.TrimSpace(os.Getenv(prefix + "REDIRECT_URL"))
+		if redirectURL == "" {
+			redirectURL = frontendURL + "/oauth/" + name + "/callback"
+		}
+
+		provider, err := NewOIDCProvider(
+			strings.TrimSpace(os.Getenv(prefix+"ISSUER")),
+			strings.TrimSpace(os.Getenv(prefix+"CLIENT_ID")),
+			strings.TrimSpace(os.Getenv(prefix+"CLIENT_SECRET")),
+			redirectURL,
+		)
+		if err != nil {
+			log.Printf("Error configuring login provider %s: %v\n", name, err)
+			continue
+		}
+		providers[name] = provider
+	}
+	return providers
+}
+
+func (p *OIDCProvider) AuthCodeURL(state, codeVerifier, nonce string) string {
+	return p.config.AuthCodeURL(state, oauth2.S256ChallengeOption(codeVerifier), oidc.Nonce(nonce))
+}
+
+func (p *OIDCProvider) Exchange(code, codeVerifier, nonce string) (*carwise.ExternalIdentity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	token, err := p.config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify id_token: %w", err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("id_token nonce does not match")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+		Picture       string `json:"picture"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse id_token claims: %w", err)
+	}
+
+	firstName, lastName := claims.GivenName, claims.FamilyName
+	if firstName == "" && lastName == "" {
+		firstName, lastName, _ = strings.Cut(strings.TrimSpace(claims.Name), " ")
+	}
+
+	return &carwise.ExternalIdentity{
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		FirstName:     firstName,
+		LastName:      lastName,
+		ImageUrl:      claims.Picture,
+	}, nil
+}
@@ -0,0 +1,465 @@
+package infra
+
+import (
+	"carwise"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	mockClientID     = "carwise-test"
+	mockClientSecret = "secret"
+	mockRedirectURL  = "http://localhost:3000/oauth/mock/callback"
+)
+
+type mockAuthorization struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+	claims      map[string]interface{}
+}
+
+// mockOIDCServer is a minimal OpenID provider: discovery, JWKS, an authorize
+// endpoint that consents immediately and a token endpoint enforcing PKCE.
+type mockOIDCServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu     sync.Mutex
+	claims map[string]interface{}
+	codes  map[string]mockAuthorization
+}
+
+func newMockOIDCServer(t *testing.T) *mockOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+
+	server := &mockOIDCServer{key: key, codes: map[string]mockAuthorization{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", server.discovery)
+	mux.HandleFunc("/jwks", server.jwks)
+	mux.HandleFunc("/authorize", server.authorize)
+	mux.HandleFunc("/token", server.token)
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// setClaims configures the id_token claims issued for the next authorization.
+func (s *mockOIDCServer) setClaims(claims map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claims = claims
+}
+
+func (s *mockOIDCServer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                s.URL,
+		"authorization_endpoint":                s.URL + "/authorize",
+		"token_endpoint":                        s.URL + "/token",
+		"jwks_uri":                              s.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (s *mockOIDCServer) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test",
+			"alg": "RS256",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *mockOIDCServer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != mockClientID || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	code := "code-" + query.Get("state")
+	s.codes[code] = mockAuthorization{
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		redirectURI: query.Get("redirect_uri"),
+		claims:      s.claims,
+	}
+	s.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", query.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *mockOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != mockClientID || clientSecret != mockClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	s.mu.Lock()
+	authorization, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	verifier := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("redirect_uri") != authorization.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(verifier[:]) != authorization.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := map[string]interface{}{
+		"iss":   s.URL,
+		"sub":   "mock-subject",
+		"aud":   mockClientID,
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": authorization.nonce,
+	}
+	for key, value := range authorization.claims {
+		claims[key] = value
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     s.sign(claims),
+	})
+}
+
+func (s *mockOIDCServer) sign(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "test", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// authorizeAt follows an authorization URL like a browser would and returns
+// the parameters the provider redirected back with.
+func authorizeAt(t *testing.T, authURL string) url.Values {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("authorization request failed: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		t.Fatalf("authorization request returned status %d", response.StatusCode)
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("invalid redirect: %v", err)
+	}
+	if !strings.HasPrefix(location.String(), mockRedirectURL) {
+		t.Fatalf("redirected to %s, want %s", location, mockRedirectURL)
+	}
+	return location.Query()
+}
+
+func newMockProvider(t *testing.T, server *mockOIDCServer) *OIDCProvider {
+	provider, err := NewOIDCProvider(server.URL, mockClientID, mockClientSecret, mockRedirectURL)
+	if err != nil {
+		t.Fatalf("failed to configure provider: %v", err)
+	}
+	return provider
+}
+
+func TestOIDCProviderAuthCodeURL(t *testing.T) {
+	server := newMockOIDCServer(t)
+	provider := newMockProvider(t, server)
+
+	authURL, err := url.Parse(provider.AuthCodeURL("state-1", "verifier-1", "nonce-1"))
+	if err != nil {
+		t.Fatalf("invalid auth url: %v", err)
+	}
+	challenge := sha256.Sum256([]byte("verifier-1"))
+	query := authURL.Query()
+	for key, want := range map[string]string{
+		"client_id":             mockClientID,
+		"redirect_uri":          mockRedirectURL,
+		"response_type":         "code",
+		"state":                 "state-1",
+		"nonce":                 "nonce-1",
+		"code_challenge":        base64.RawURLEncoding.EncodeToString(challenge[:]),
+		"code_challenge_method": "S256",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s: got %q, want %q", key, got, want)
+		}
+	}
+	if !strings.Contains(query.Get("scope"), "openid") {
+		t.Errorf("scope %q does not request openid", query.Get("scope"))
+	}
+	if query.Get("code_verifier") != "" {
+		t.Error("the code verifier must not be sent to the authorization endpoint")
+	}
+}
+
+func TestOIDCProviderExchange(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		verifier string
+		nonce    string
+		want     *carwise.ExternalIdentity
+		wantErr  bool
+	}{
+		{
+			name: "given and family name",
+			claims: map[string]interface{}{
+				"email": "jane@example.com", "email_verified": true,
+				"given_name": "Jane", "family_name": "Doe", "picture": "https://example.com/jane.png",
+			},
+			want: &carwise.ExternalIdentity{
+				Subject: "mock-subject", Email: "jane@example.com", EmailVerified: true,
+				FirstName: "Jane", LastName: "Doe", ImageUrl: "https://example.com/jane.png",
+			},
+		},
+		{
+			name:   "full name only",
+			claims: map[string]interface{}{"email": "jane@example.com", "name": "Jane Mary Doe"},
+			want: &carwise.ExternalIdentity{
+				Subject: "mock-subject", Email: "jane@example.com", FirstName: "Jane", LastName: "Mary Doe",
+			},
+		},
+		{name: "wrong code verifier", verifier: "another-verifier", wantErr: true},
+		{name: "nonce mismatch", nonce: "another-nonce", wantErr: true},
+		{name: "wrong audience", claims: map[string]interface{}{"aud": "another-client"}, wantErr: true},
+		{name: "wrong issuer", claims: map[string]interface{}{"iss": "https://evil.example.com"}, wantErr: true},
+		{name: "expired id_token", claims: map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}, wantErr: true},
+	}
+
+	server := newMockOIDCServer(t)
+	provider := newMockProvider(t, server)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.setClaims(tt.claims)
+			state := strings.ReplaceAll(tt.name, " ", "-")
+			params := authorizeAt(t, provider.AuthCodeURL(state, "verifier-"+state, "nonce-"+state))
+			if params.Get("state") != state {
+				t.Fatalf("got state %q, want %q", params.Get("state"), state)
+			}
+
+			verifier, nonce := "verifier-"+state, "nonce-"+state
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			identity, err := provider.Exchange(params.Get("code"), verifier, nonce)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", identity)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *identity != *tt.want {
+				t.Errorf("got %+v, want %+v", identity, tt.want)
+			}
+
+			if _, err := provider.Exchange(params.Get("code"), verifier, nonce); err == nil {
+				t.Error("expected the authorization code to be single use")
+			}
+		})
+	}
+}
+
+type memoryOAuthStateRepository struct {
+	states map[string]carwise.OAuthState
+}
+
+func (r *memoryOAuthStateRepository) SaveState(state string, data *carwise.OAuthState, ttl time.Duration) error {
+	r.states[state] = *data
+	return nil
+}
+
+func (r *memoryOAuthStateRepository) ConsumeState(state string) (*carwise.OAuthState, error) {
+	data, ok := r.states[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(r.states, state)
+	return &data, nil
+}
+
+type memoryUserRepository struct {
+	carwise.UserRepository
+	users map[string]*carwise.User
+}
+
+func (r *memoryUserRepository) Create(user *carwise.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) GetByID(id string) (*carwise.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+	return nil, carwise.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) GetByEmail(email string) (*carwise.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, carwise.ErrUserNotFound
+}
+
+func (r *memoryUserRepository) UpdateLastLogin(id string, at time.Time) error {
+	return nil
+}
+
+type memoryUserIdentityRepository struct {
+	carwise.UserIdentityRepository
+	identities []carwise.UserIdentity
+}
+
+func (r *memoryUserIdentityRepository) Create(identity *carwise.UserIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *memoryUserIdentityRepository) GetByProviderSubject(provider, subject string) (*carwise.UserIdentity, error) {
+	for idx := range r.identities {
+		if r.identities[idx].Provider == provider && r.identities[idx].Subject == subject {
+			return &r.identities[idx], nil
+		}
+	}
+	return nil, nil
+}
+
+type discardLoginEventRepository struct {
+	carwise.LoginEventRepository
+}
+
+func (r *discardLoginEventRepository) Create(event *carwise.LoginEvent) error {
+	return nil
+}
+
+func TestSocialLoginState(t *testing.T) {
+	server := newMockOIDCServer(t)
+	server.setClaims(map[string]interface{}{
+		"email": "jane@example.com", "email_verified": true, "given_name": "Jane", "family_name": "Doe",
+	})
+
+	states := &memoryOAuthStateRepository{states: map[string]carwise.OAuthState{}}
+	users := &memoryUserRepository{users: map[string]*carwise.User{}}
+	interactor := carwise.NewInteractor(carwise.Services{
+		IdentityProviders: map[string]carwise.IdentityProvider{
+			"mock":  newMockProvider(t, server),
+			"other": newMockProvider(t, server),
+		},
+		OAuthStateRepo:   states,
+		UserRepo:         users,
+		UserIdentityRepo: &memoryUserIdentityRepository{},
+		LoginEventRepo:   &discardLoginEventRepository{},
+	}, carwise.Config{})
+
+	start := func(provider string) url.Values {
+		authURL, errors := interactor.StartSocialLogin(provider)
+		if errors != nil {
+			t.Fatalf("failed to start login: %v", errors)
+		}
+		return authorizeAt(t, authURL)
+	}
+	login := func(provider string, params url.Values) []string {
+		_, errors := interactor.SocialLogin(provider, carwise.SocialLoginRequest{
+			Code:  params.Get("code"),
+			State: params.Get("state"),
+		}, "127.0.0.1", "test")
+		return errors
+	}
+
+	if _, errors := interactor.StartSocialLogin("unknown"); errors == nil {
+		t.Error("expected an unsupported provider to be rejected")
+	}
+
+	t.Run("unknown state", func(t *testing.T) {
+		params := start("mock")
+		params.Set("state", "forged")
+		if errors := login("mock", params); len(errors) == 0 || errors[0] != "Invalid or expired login request." {
+			t.Errorf("got %v, want an invalid state error", errors)
+		}
+	})
+
+	t.Run("state issued for another provider", func(t *testing.T) {
+		params := start("other")
+		if errors := login("mock", params); len(errors) == 0 || errors[0] != "Invalid or expired login request." {
+			t.Errorf("got %v, want an invalid state error", errors)
+		}
+	})
+
+	t.Run("valid login", func(t *testing.T) {
+		params := start("mock")
+		if errors := login("mock", params); errors != nil {
+			t.Fatalf("unexpected errors: %v", errors)
+		}
+		user, err := users.GetByEmail("jane@example.com")
+		if err != nil {
+			t.Fatalf("expected the user to be created: %v", err)
+		}
+		if user.FirstName != "Jane" || user.LastName != "Doe" || !user.EmailVerified {
+			t.Errorf("got %+v", user)
+		}
+
+		if errors := login("mock", params); len(errors) == 0 || errors[0] != "Invalid or expired login request." {
+			t.Errorf("expected the state to be single use, got %v", errors)
+		}
+	})
+}
@@ -0,0 +1,263 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const importJobColumns = `
+			id, 
+			organization_id, 
+			user_id, 
+			format, 
+			full_sync, 
+			status, 
+			total_rows, 
+			created_count, 
+			updated_count, 
+			removed_count, 
+			failed_count, 
+			error, 
+			created_at, 
+			finished_at`
+
+type ImportJobRepository struct {
+	db *sql.DB
+}
+
+func NewImportJobRepository() *ImportJobRepository {
+	database := ConnectDb()
+	return &ImportJobRepository{db: database}
+}
+
+func (r *ImportJobRepository) Create(job *carwise.ImportJob) error {
+	query := `
+		INSERT INTO import_jobs (
+			id, 
+			organization_id, 
+			user_id, 
+			format, 
+			full_sync, 
+			status, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`
+	_, err := r.db.Exec(query,
+		job.ID,
+		job.OrganizationID,
+		job.UserID,
+		job.Format,
+		job.FullSync,
+		job.Status,
+		job.CreatedAt,
+	)
+	if err != nil {
+		// import_jobs_unfinished_idx allows one queued or processing job per
+		// organization.
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" && pqErr.Constraint == "import_jobs_unfinished_idx" {
+			return carwise.ErrImportRunning
+		}
+		return fmt.Errorf("failed to create import job: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) Update(job *carwise.ImportJob) error {
+	query := `
+		UPDATE import_jobs 
+		SET 
+			status = $1, 
+			total_rows = $2, 
+			created_count = $3, 
+			updated_count = $4, 
+			removed_count = $5, 
+			failed_count = $6, 
+			error = $7, 
+			finished_at = $8 
+		WHERE id = $9`
+
+	_, err := r.db.Exec(query,
+		job.Status,
+		job.TotalRows,
+		job.CreatedCount,
+		job.UpdatedCount,
+		job.RemovedCount,
+		job.FailedCount,
+		job.Error,
+		sql.NullTime{Time: job.FinishedAt, Valid: !job.FinishedAt.IsZero()},
+		job.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update import job: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) GetByID(id string) (*carwise.ImportJob, error) {
+	query := `
+		SELECT ` + importJobColumns + `
+		FROM import_jobs
+		WHERE id = $1
+	`
+	job, err := scanImportJob(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch import job: %w", err)
+	}
+	return job, nil
+}
+
+func (r *ImportJobRepository) GetByOrganization(organizationID string, page, limit int) ([]carwise.ImportJob, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + importJobColumns + `
+		FROM import_jobs
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(query, organizationID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch import jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []carwise.ImportJob
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan import job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (r *ImportJobRepository) FailStale(before time.Time, reason string) (int64, error) {
+	query := `
+		UPDATE import_jobs 
+		SET 
+			status = $1, 
+			error = $2, 
+			finished_at = NOW() 
+		WHERE status IN ($3, $4) AND created_at <= $5`
+
+	result, err := r.db.Exec(query, carwise.ImportJobStatusFailed, reason, carwise.ImportJobStatusQueued, carwise.ImportJobStatusProcessing, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail stale import jobs: %w", err)
+	}
+
+	failed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	return failed, nil
+}
+
+func (r *ImportJobRepository) AddRows(rows []carwise.ImportJobRow) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO import_job_rows (job_id, row_index, stock_number, result, car_id, errors) VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare import row insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		_, err = stmt.Exec(row.JobID, row.Row, row.StockNumber, row.Result, row.CarID, pq.Array(row.Errors))
+		if err != nil {
+			return fmt.Errorf("failed to add import row: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import rows: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ImportJobRepository) GetRows(jobID string) ([]carwise.ImportJobRow, error) {
+	query := `
+		SELECT 
+			job_id, 
+			row_index, 
+			stock_number, 
+			result, 
+			COALESCE(car_id, ''), 
+			errors 
+		FROM import_job_rows 
+		WHERE job_id = $1 
+		ORDER BY row_index`
+
+	rows, err := r.db.Query(query, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch import rows: %w", err)
+	}
+	defer rows.Close()
+
+	var result []carwise.ImportJobRow
+	for rows.Next() {
+		var row carwise.ImportJobRow
+		if err := rows.Scan(
+			&row.JobID,
+			&row.Row,
+			&row.StockNumber,
+			&row.Result,
+			&row.CarID,
+			pq.Array(&row.Errors),
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan import row: %w", err)
+		}
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return result, nil
+}
+
+func scanImportJob(row rowScanner) (*carwise.ImportJob, error) {
+	job := &carwise.ImportJob{}
+	var finishedAt sql.NullTime
+	err := row.Scan(
+		&job.ID,
+		&job.OrganizationID,
+		&job.UserID,
+		&job.Format,
+		&job.FullSync,
+		&job.Status,
+		&job.TotalRows,
+		&job.CreatedCount,
+		&job.UpdatedCount,
+		&job.RemovedCount,
+		&job.FailedCount,
+		&job.Error,
+		&job.CreatedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.FinishedAt = finishedAt.Time
+	return job, nil
+}
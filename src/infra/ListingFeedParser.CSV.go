@@ -0,0 +1,63 @@
+package infra
+
+import (
+	"carwise"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type CSVFeedParser struct{}
+
+func NewCSVFeedParser() *CSVFeedParser {
+	return &CSVFeedParser{}
+}
+
+// NewListingFeedParsers returns the parsers for every supported feed format,
+// keyed by the format name accepted by the import endpoint.
+func NewListingFeedParsers() map[string]carwise.ListingFeedParser {
+	return map[string]carwise.ListingFeedParser{
+		carwise.ImportFormatCSV: NewCSVFeedParser(),
+		carwise.ImportFormatXML: NewXMLFeedParser(),
+	}
+}
+
+func (p *CSVFeedParser) Parse(feed io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(feed)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("feed is empty")
+		}
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+	for idx, column := range header {
+		header[idx] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+	}
+
+	var records []map[string]string
+	for {
+		values, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read line: %v", err)
+		}
+
+		record := make(map[string]string)
+		for idx, value := range values {
+			if idx < len(header) {
+				record[header[idx]] = value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCSVFeedParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		feed    string
+		want    []map[string]string
+		wantErr bool
+	}{
+		{
+			name: "records keyed by header",
+			feed: "stock_number,brand,price\nSTK-1,BMW,100\nSTK-2,Audi,200\n",
+			want: []map[string]string{
+				{"stock_number": "STK-1", "brand": "BMW", "price": "100"},
+				{"stock_number": "STK-2", "brand": "Audi", "price": "200"},
+			},
+		},
+		{
+			name: "header is normalized",
+			feed: "\ufeffStock_Number , BRAND\nSTK-1,BMW\n",
+			want: []map[string]string{{"stock_number": "STK-1", "brand": "BMW"}},
+		},
+		{
+			name: "leading spaces and quoted commas",
+			feed: "stock_number,title\nSTK-1, \"Golf, low mileage\"\n",
+			want: []map[string]string{{"stock_number": "STK-1", "title": "Golf, low mileage"}},
+		},
+		{
+			name: "short and long rows",
+			feed: "stock_number,brand\nSTK-1\nSTK-2,BMW,extra\n",
+			want: []map[string]string{
+				{"stock_number": "STK-1"},
+				{"stock_number": "STK-2", "brand": "BMW"},
+			},
+		},
+		{
+			name: "header only",
+			feed: "stock_number,brand\n",
+			want: nil,
+		},
+		{
+			name:    "empty feed",
+			feed:    "",
+			wantErr: true,
+		},
+		{
+			name:    "unterminated quote",
+			feed:    "stock_number,title\nSTK-1,\"Golf\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewCSVFeedParser().Parse(strings.NewReader(tt.feed))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package infra
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type xmlFeed struct {
+	Listings []xmlListing `xml:"listing"`
+}
+
+type xmlListing struct {
+	Fields []xmlField `xml:",any"`
+}
+
+type xmlField struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+type XMLFeedParser struct{}
+
+func NewXMLFeedParser() *XMLFeedParser {
+	return &XMLFeedParser{}
+}
+
+func (p *XMLFeedParser) Parse(feed io.Reader) ([]map[string]string, error) {
+	var document xmlFeed
+	if err := xml.NewDecoder(feed).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode feed: %v", err)
+	}
+
+	var records []map[string]string
+	for _, listing := range document.Listings {
+		record := make(map[string]string)
+		for _, field := range listing.Fields {
+			record[strings.ToLower(field.XMLName.Local)] = field.Value
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
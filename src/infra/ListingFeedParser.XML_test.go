@@ -0,0 +1,77 @@
+package infra
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestXMLFeedParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		feed    string
+		want    []map[string]string
+		wantErr bool
+	}{
+		{
+			name: "one record per listing",
+			feed: `<?xml version="1.0" encoding="UTF-8"?>
+<listings>
+	<listing><stock_number>STK-1</stock_number><brand>BMW</brand></listing>
+	<listing><stock_number>STK-2</stock_number><brand>Audi</brand></listing>
+</listings>`,
+			want: []map[string]string{
+				{"stock_number": "STK-1", "brand": "BMW"},
+				{"stock_number": "STK-2", "brand": "Audi"},
+			},
+		},
+		{
+			name: "field names are lowercased",
+			feed: `<listings><listing><Stock_Number>STK-1</Stock_Number></listing></listings>`,
+			want: []map[string]string{{"stock_number": "STK-1"}},
+		},
+		{
+			name: "escaped text",
+			feed: `<listings><listing><title>Golf &amp; more</title><description><![CDATA[<b>M paket</b>]]></description></listing></listings>`,
+			want: []map[string]string{{"title": "Golf & more", "description": "<b>M paket</b>"}},
+		},
+		{
+			name: "other elements are ignored",
+			feed: `<listings><car><stock_number>STK-1</stock_number></car></listings>`,
+			want: nil,
+		},
+		{
+			name: "no listings",
+			feed: `<listings></listings>`,
+			want: nil,
+		},
+		{
+			name:    "malformed document",
+			feed:    `<listings><listing><stock_number>STK-1</listing></listings>`,
+			wantErr: true,
+		},
+		{
+			name:    "empty feed",
+			feed:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewXMLFeedParser().Parse(strings.NewReader(tt.feed))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
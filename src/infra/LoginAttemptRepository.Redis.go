@@ -0,0 +1,68 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type LoginAttemptRepository struct {
+	client *redis.Client
+}
+
+func NewLoginAttemptRepository() *LoginAttemptRepository {
+	return &LoginAttemptRepository{client: ConnectRedis()}
+}
+
+func (r *LoginAttemptRepository) RecordFailure(key string, window time.Duration) (int64, error) {
+	redisKey := fmt.Sprintf("login-failures:%s", key)
+	failures, err := r.client.Incr(context.Background(), redisKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to record login failure in Redis: %v", err)
+	}
+
+	if failures == 1 {
+		err = r.client.Expire(context.Background(), redisKey, window).Err()
+		if err != nil {
+			return 0, fmt.Errorf("failed to set login failure window in Redis: %v", err)
+		}
+	}
+
+	return failures, nil
+}
+
+func (r *LoginAttemptRepository) ResetFailures(key string) error {
+	redisKey := fmt.Sprintf("login-failures:%s", key)
+	err := r.client.Del(context.Background(), redisKey).Err()
+	if err != nil {
+		return fmt.Errorf("failed to reset login failures in Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) Lock(key string, ttl time.Duration) error {
+	redisKey := fmt.Sprintf("login-lock:%s", key)
+	err := r.client.Set(context.Background(), redisKey, "locked", ttl).Err()
+	if err != nil {
+		return fmt.Errorf("failed to lock login in Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) LockedFor(key string) (time.Duration, error) {
+	redisKey := fmt.Sprintf("login-lock:%s", key)
+	ttl, err := r.client.PTTL(context.Background(), redisKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check login lock in Redis: %v", err)
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
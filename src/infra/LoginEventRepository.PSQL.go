@@ -0,0 +1,121 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type LoginEventRepository struct {
+	db *sql.DB
+}
+
+func NewLoginEventRepository() *LoginEventRepository {
+	database := ConnectDb()
+	return &LoginEventRepository{db: database}
+}
+
+func (r *LoginEventRepository) Create(event *carwise.LoginEvent) error {
+	query := `
+		INSERT INTO login_events (
+			id, 
+			user_id, 
+			email, 
+			ip, 
+			user_agent, 
+			method, 
+			outcome, 
+			reason, 
+			created_at
+		) VALUES (
+			$1, NULLIF($2, ''), $3, $4, $5, $6, $7, $8, $9
+		)`
+	_, err := r.db.Exec(query,
+		event.ID,
+		event.UserID,
+		event.Email,
+		event.IP,
+		event.UserAgent,
+		event.Method,
+		event.Outcome,
+		event.Reason,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create login event: %w", err)
+	}
+	return nil
+}
+
+func (r *LoginEventRepository) Search(query, userID, outcome string, page, limit int) ([]carwise.LoginEvent, error) {
+	offset := (page - 1) * limit
+
+	sqlQuery := `
+		SELECT 
+			id, 
+			COALESCE(user_id, ''), 
+			email, 
+			ip, 
+			user_agent, 
+			method, 
+			outcome, 
+			reason, 
+			created_at 
+		FROM login_events
+	`
+	conditions := []string{}
+	args := []interface{}{}
+
+	if query != "" {
+		placeholder := "$" + fmt.Sprint(len(args)+1)
+		conditions = append(conditions, "(email ILIKE "+placeholder+" OR ip ILIKE "+placeholder+")")
+		args = append(args, "%"+query+"%")
+	}
+	if userID != "" {
+		conditions = append(conditions, "user_id = $"+fmt.Sprint(len(args)+1))
+		args = append(args, userID)
+	}
+	if outcome != "" {
+		conditions = append(conditions, "outcome = $"+fmt.Sprint(len(args)+1))
+		args = append(args, outcome)
+	}
+
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sqlQuery += " ORDER BY created_at DESC LIMIT $" + fmt.Sprint(len(args)+1) + " OFFSET $" + fmt.Sprint(len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search login events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []carwise.LoginEvent
+	for rows.Next() {
+		var event carwise.LoginEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.UserID,
+			&event.Email,
+			&event.IP,
+			&event.UserAgent,
+			&event.Method,
+			&event.Outcome,
+			&event.Reason,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan login event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return events, nil
+}
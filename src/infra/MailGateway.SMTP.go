@@ -1,28 +1,55 @@
 package infra
 
 import (
+	"bytes"
+	"carwise"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
 	"net/smtp"
+	"net/textproto"
 	"os"
+	"sort"
+	"strings"
+	"time"
 )
 
+const defaultMailFrom = "Carwise <app.carwise@gmail.com>"
+
 type MailGateway struct {
 	Host     string
 	Port     string
 	User     string
 	Password string
+	From     string
 }
 
 func NewMailGateway() *MailGateway {
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = defaultMailFrom
+	}
 	return &MailGateway{
 		Host:     os.Getenv("SMTP_HOST"),
 		Port:     os.Getenv("SMTP_PORT"),
 		User:     os.Getenv("SMTP_USER"),
 		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
 	}
 }
 
-func (GW *MailGateway) Send(To string, Body []byte) error {
+func (GW *MailGateway) Send(email *carwise.Email) error {
+	body, err := GW.buildMessage(email)
+	if err != nil {
+		return err
+	}
+
 	auth := smtp.PlainAuth("", GW.User, GW.Password, GW.Host)
 
 	tlsconfig := &tls.Config{
@@ -45,7 +72,7 @@ func (GW *MailGateway) Send(To string, Body []byte) error {
 		return err
 	}
 
-	if err = c.Rcpt(To); err != nil {
+	if err = c.Rcpt(email.To); err != nil {
 		return err
 	}
 
@@ -54,7 +81,7 @@ func (GW *MailGateway) Send(To string, Body []byte) error {
 		return err
 	}
 
-	_, err = w.Write(Body)
+	_, err = w.Write(body)
 	if err != nil {
 		return err
 	}
@@ -66,3 +93,148 @@ func (GW *MailGateway) Send(To string, Body []byte) error {
 
 	return nil
 }
+
+// buildMessage encodes the email as a multipart/alternative message with a
+// plain text and an HTML part, wrapped in multipart/mixed when it carries
+// attachments.
+func (GW *MailGateway) buildMessage(email *carwise.Email) ([]byte, error) {
+	from, err := mail.ParseAddress(GW.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(email.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse recipient address: %w", err)
+	}
+	messageID, err := newMessageID(from.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	writeHeader(&message, "From", from.String())
+	writeHeader(&message, "To", to.String())
+	writeHeader(&message, "Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	writeHeader(&message, "Date", time.Now().Format(time.RFC1123Z))
+	writeHeader(&message, "Message-ID", messageID)
+	writeHeader(&message, "MIME-Version", "1.0")
+
+	keys := []string{}
+	for key := range email.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writeHeader(&message, key, email.Headers[key])
+	}
+
+	var alternative bytes.Buffer
+	alternativeWriter := multipart.NewWriter(&alternative)
+	err = writeTextPart(alternativeWriter, "text/plain; charset=utf-8", email.Text)
+	if err != nil {
+		return nil, err
+	}
+	err = writeTextPart(alternativeWriter, "text/html; charset=utf-8", email.HTML)
+	if err != nil {
+		return nil, err
+	}
+	if err := alternativeWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	alternativeType := "multipart/alternative; boundary=" + alternativeWriter.Boundary()
+
+	if len(email.Attachments) == 0 {
+		writeHeader(&message, "Content-Type", alternativeType)
+		message.WriteString("\r\n")
+		message.Write(alternative.Bytes())
+		return message.Bytes(), nil
+	}
+
+	var mixed bytes.Buffer
+	mixedWriter := multipart.NewWriter(&mixed)
+	part, err := mixedWriter.CreatePart(textproto.MIMEHeader{"Content-Type": {alternativeType}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if _, err := part.Write(alternative.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+	for _, attachment := range email.Attachments {
+		err = writeAttachmentPart(mixedWriter, attachment)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := mixedWriter.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email body: %w", err)
+	}
+
+	writeHeader(&message, "Content-Type", "multipart/mixed; boundary="+mixedWriter.Boundary())
+	message.WriteString("\r\n")
+	message.Write(mixed.Bytes())
+	return message.Bytes(), nil
+}
+
+func writeHeader(message *bytes.Buffer, key, value string) {
+	message.WriteString(key + ": " + value + "\r\n")
+}
+
+func writeTextPart(writer *multipart.Writer, contentType, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode email body: %w", err)
+	}
+	return nil
+}
+
+func writeAttachmentPart(writer *multipart.Writer, attachment carwise.EmailAttachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode email attachment: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Content)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return fmt.Errorf("failed to encode email attachment: %w", err)
+		}
+		encoded = encoded[76:]
+	}
+	if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+		return fmt.Errorf("failed to encode email attachment: %w", err)
+	}
+	return nil
+}
+
+func newMessageID(address string) (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate message id: %w", err)
+	}
+
+	domain := "carwise"
+	if at := strings.LastIndex(address, "@"); at != -1 {
+		domain = address[at+1:]
+	}
+	return fmt.Sprintf("<%s.%s@%s>", time.Now().Format("20060102150405"), hex.EncodeToString(token), domain), nil
+}
@@ -0,0 +1,247 @@
+package infra
+
+import (
+	"bytes"
+	"carwise"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+type rawMailPart struct {
+	header map[string][]string
+	body   []byte
+}
+
+// readRawParts splits a multipart body without decoding the parts, so the
+// tests see the transfer encoding that goes on the wire.
+func readRawParts(t *testing.T, contentType string, body []byte) (string, []rawMailPart) {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("invalid content type %q: %v", contentType, err)
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		t.Fatalf("content type %q has no boundary", contentType)
+	}
+	if !bytes.Contains(body, []byte("--"+boundary+"--")) {
+		t.Errorf("%s body is missing its closing boundary", mediaType)
+	}
+
+	var parts []rawMailPart
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read %s part: %v", mediaType, err)
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("failed to read %s part: %v", mediaType, err)
+		}
+		parts = append(parts, rawMailPart{header: part.Header, body: content})
+	}
+	return boundary, parts
+}
+
+func checkLineLength(t *testing.T, name string, body []byte, max int) {
+	t.Helper()
+	for _, line := range strings.Split(string(body), "\r\n") {
+		if len(line) > max {
+			t.Errorf("%s line is %d characters long, want at most %d", name, len(line), max)
+		}
+	}
+}
+
+func TestMailGatewayBuildMessage(t *testing.T) {
+	longLine := strings.Repeat("Araç ilanınız yayında. ", 10)
+
+	tests := []struct {
+		name  string
+		email carwise.Email
+	}{
+		{
+			name: "plain ascii",
+			email: carwise.Email{
+				To:      "John Doe <john@example.com>",
+				Subject: "Verify your email",
+				Text:    "Hello John\n",
+				HTML:    "<p>Hello John</p>",
+			},
+		},
+		{
+			name: "non-ascii subject and long lines",
+			email: carwise.Email{
+				To:      "ayse@example.com",
+				Subject: "İlanınızın süresi doluyor",
+				Text:    longLine + "\n",
+				HTML:    "<p style=\"margin:0\">" + longLine + "</p>",
+			},
+		},
+		{
+			name: "extra headers",
+			email: carwise.Email{
+				To:      "john@example.com",
+				Subject: "New cars for your search",
+				Text:    "Hello\n",
+				HTML:    "<p>Hello</p>",
+				Headers: map[string]string{
+					"List-Unsubscribe":      "<https://carwise.example/unsubscribe>",
+					"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+				},
+			},
+		},
+		{
+			name: "attachments",
+			email: carwise.Email{
+				To:      "john@example.com",
+				Subject: "Your account data",
+				Text:    "Your export is attached.\n",
+				HTML:    "<p>Your export is attached.</p>",
+				Attachments: []carwise.EmailAttachment{
+					{Filename: "export.json", ContentType: "application/json", Content: []byte(`{"cars":[]}`)},
+					{Filename: "photo ı.bin", Content: bytes.Repeat([]byte{0, 1, 2, 250}, 64)},
+				},
+			},
+		},
+	}
+
+	gateway := &MailGateway{From: defaultMailFrom}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := gateway.buildMessage(&tt.email)
+			if err != nil {
+				t.Fatalf("buildMessage: %v", err)
+			}
+			message, err := mail.ReadMessage(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("message does not parse: %v", err)
+			}
+
+			for key, want := range map[string]string{
+				"From":         "\"Carwise\" <app.carwise@gmail.com>",
+				"MIME-Version": "1.0",
+			} {
+				if got := message.Header.Get(key); got != want {
+					t.Errorf("%s header %q, want %q", key, got, want)
+				}
+			}
+			to, err := message.Header.AddressList("To")
+			if err != nil || len(to) != 1 {
+				t.Fatalf("To header %q does not parse: %v", message.Header.Get("To"), err)
+			}
+			if message.Header.Get("Message-ID") == "" || message.Header.Get("Date") == "" {
+				t.Error("Message-ID and Date headers are required")
+			}
+			for key, want := range tt.email.Headers {
+				if got := message.Header.Get(key); got != want {
+					t.Errorf("%s header %q, want %q", key, got, want)
+				}
+			}
+
+			rawSubject := message.Header.Get("Subject")
+			for _, r := range rawSubject {
+				if r > 127 {
+					t.Fatalf("subject header %q is not ASCII", rawSubject)
+				}
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(rawSubject)
+			if err != nil || subject != tt.email.Subject {
+				t.Errorf("subject decodes to %q (%v), want %q", subject, err, tt.email.Subject)
+			}
+
+			body, err := io.ReadAll(message.Body)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+			contentType := message.Header.Get("Content-Type")
+			if len(tt.email.Attachments) > 0 {
+				if !strings.HasPrefix(contentType, "multipart/mixed;") {
+					t.Fatalf("content type %q, want multipart/mixed", contentType)
+				}
+				mixedBoundary, parts := readRawParts(t, contentType, body)
+				if len(parts) != len(tt.email.Attachments)+1 {
+					t.Fatalf("got %d mixed parts, want %d", len(parts), len(tt.email.Attachments)+1)
+				}
+				checkAttachments(t, parts[1:], tt.email.Attachments)
+
+				contentType = parts[0].header["Content-Type"][0]
+				body = parts[0].body
+				if _, params, _ := mime.ParseMediaType(contentType); params["boundary"] == mixedBoundary {
+					t.Error("nested multipart reuses the outer boundary")
+				}
+			}
+
+			if !strings.HasPrefix(contentType, "multipart/alternative;") {
+				t.Fatalf("content type %q, want multipart/alternative", contentType)
+			}
+			_, parts := readRawParts(t, contentType, body)
+			if len(parts) != 2 {
+				t.Fatalf("got %d alternative parts, want 2", len(parts))
+			}
+			for idx, want := range []struct{ contentType, content string }{
+				{"text/plain; charset=utf-8", tt.email.Text},
+				{"text/html; charset=utf-8", tt.email.HTML},
+			} {
+				part := parts[idx]
+				if got := part.header["Content-Type"][0]; got != want.contentType {
+					t.Errorf("part %d content type %q, want %q", idx, got, want.contentType)
+				}
+				if got := part.header["Content-Transfer-Encoding"][0]; got != "quoted-printable" {
+					t.Errorf("part %d transfer encoding %q, want quoted-printable", idx, got)
+				}
+				checkLineLength(t, want.contentType, part.body, 76)
+				decoded, err := io.ReadAll(quotedprintable.NewReader(bytes.NewReader(part.body)))
+				if err != nil {
+					t.Fatalf("part %d is not valid quoted-printable: %v", idx, err)
+				}
+				// Text parts travel with CRLF line breaks.
+				if wantContent := strings.ReplaceAll(want.content, "\n", "\r\n"); string(decoded) != wantContent {
+					t.Errorf("part %d decodes to %q, want %q", idx, decoded, wantContent)
+				}
+			}
+		})
+	}
+}
+
+func checkAttachments(t *testing.T, parts []rawMailPart, attachments []carwise.EmailAttachment) {
+	t.Helper()
+	for idx, attachment := range attachments {
+		part := parts[idx]
+
+		wantType := attachment.ContentType
+		if wantType == "" {
+			wantType = "application/octet-stream"
+		}
+		mediaType, params, err := mime.ParseMediaType(part.header["Content-Type"][0])
+		if err != nil || mediaType != wantType || params["name"] != attachment.Filename {
+			t.Errorf("attachment %d content type %q (%v), want %s named %q", idx, part.header["Content-Type"][0], err, wantType, attachment.Filename)
+		}
+		disposition, params, err := mime.ParseMediaType(part.header["Content-Disposition"][0])
+		if err != nil || disposition != "attachment" || params["filename"] != attachment.Filename {
+			t.Errorf("attachment %d disposition %q (%v), want attachment named %q", idx, part.header["Content-Disposition"][0], err, attachment.Filename)
+		}
+		if got := part.header["Content-Transfer-Encoding"][0]; got != "base64" {
+			t.Errorf("attachment %d transfer encoding %q, want base64", idx, got)
+		}
+
+		checkLineLength(t, attachment.Filename, part.body, 76)
+		content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(part.body), "\r\n", ""))
+		if err != nil {
+			t.Fatalf("attachment %d is not valid base64: %v", idx, err)
+		}
+		if !bytes.Equal(content, attachment.Content) {
+			t.Errorf("attachment %d content %v, want %v", idx, content, attachment.Content)
+		}
+	}
+}
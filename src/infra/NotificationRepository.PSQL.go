@@ -0,0 +1,244 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const notificationColumns = `
+			n.id, 
+			n.user_id, 
+			n.type, 
+			n.title, 
+			n.body, 
+			n.link, 
+			n.data, 
+			n.read_at, 
+			n.created_at`
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository() *NotificationRepository {
+	database := ConnectDb()
+	return &NotificationRepository{db: database}
+}
+
+func (r *NotificationRepository) Create(notification *carwise.Notification) error {
+	data, err := json.Marshal(notification.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification data: %w", err)
+	}
+
+	query := `
+		INSERT INTO notifications (
+			id, 
+			user_id, 
+			type, 
+			title, 
+			body, 
+			link, 
+			data, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8
+		)`
+	_, err = r.db.Exec(query,
+		notification.ID,
+		notification.UserID,
+		notification.Type,
+		notification.Title,
+		notification.Body,
+		notification.Link,
+		data,
+		notification.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetByUser(userID string, unreadOnly bool, page, limit int) ([]carwise.Notification, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications n
+		WHERE n.user_id = $1 AND ($2 = FALSE OR n.read_at IS NULL)
+		ORDER BY n.created_at DESC
+		LIMIT $3 OFFSET $4
+	`
+	rows, err := r.db.Query(query, userID, unreadOnly, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notifications: %w", err)
+	}
+	defer rows.Close()
+
+	var notifications []carwise.Notification
+	for rows.Next() {
+		notification, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return notifications, nil
+}
+
+func (r *NotificationRepository) CountUnread(userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	return count, nil
+}
+
+func (r *NotificationRepository) MarkRead(userID, id string, at time.Time) (bool, error) {
+	query := `
+		UPDATE notifications 
+		SET read_at = COALESCE(read_at, $1) 
+		WHERE id = $2 AND user_id = $3`
+	result, err := r.db.Exec(query, at, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *NotificationRepository) MarkAllRead(userID string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE notifications SET read_at = $1 WHERE user_id = $2 AND read_at IS NULL`, at, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return nil
+}
+
+func (r *NotificationRepository) GetPreference(userID, notificationType string) (*carwise.NotificationPreference, error) {
+	query := `
+		SELECT 
+			user_id, 
+			type, 
+			in_app, 
+			email 
+		FROM notification_preferences 
+		WHERE user_id = $1 AND type = $2`
+
+	var preference carwise.NotificationPreference
+	err := r.db.QueryRow(query, userID, notificationType).Scan(&preference.UserID, &preference.Type, &preference.InApp, &preference.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch notification preference: %w", err)
+	}
+	return &preference, nil
+}
+
+func (r *NotificationRepository) GetPreferences(userID string) ([]carwise.NotificationPreference, error) {
+	query := `
+		SELECT 
+			user_id, 
+			type, 
+			in_app, 
+			email 
+		FROM notification_preferences 
+		WHERE user_id = $1`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notification preferences: %w", err)
+	}
+	defer rows.Close()
+
+	var preferences []carwise.NotificationPreference
+	for rows.Next() {
+		var preference carwise.NotificationPreference
+		if err := rows.Scan(&preference.UserID, &preference.Type, &preference.InApp, &preference.Email); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		preferences = append(preferences, preference)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return preferences, nil
+}
+
+func (r *NotificationRepository) SavePreferences(preferences []carwise.NotificationPreference) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO notification_preferences (
+			user_id, 
+			type, 
+			in_app, 
+			email
+		) VALUES (
+			$1, $2, $3, $4
+		) 
+		ON CONFLICT (user_id, type) DO UPDATE 
+		SET in_app = EXCLUDED.in_app, email = EXCLUDED.email`
+
+	for _, preference := range preferences {
+		_, err = tx.Exec(query, preference.UserID, preference.Type, preference.InApp, preference.Email)
+		if err != nil {
+			return fmt.Errorf("failed to save notification preference: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+func scanNotification(row rowScanner) (carwise.Notification, error) {
+	var notification carwise.Notification
+	var data []byte
+	var readAt sql.NullTime
+	err := row.Scan(
+		&notification.ID,
+		&notification.UserID,
+		&notification.Type,
+		&notification.Title,
+		&notification.Body,
+		&notification.Link,
+		&data,
+		&readAt,
+		&notification.CreatedAt,
+	)
+	if err != nil {
+		return notification, err
+	}
+
+	notification.ReadAt = readAt.Time
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &notification.Data); err != nil {
+			return notification, err
+		}
+	}
+	return notification, nil
+}
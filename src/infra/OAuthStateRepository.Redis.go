@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"carwise"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type OAuthStateRepository struct {
+	client *redis.Client
+}
+
+func NewOAuthStateRepository() *OAuthStateRepository {
+	return &OAuthStateRepository{client: ConnectRedis()}
+}
+
+func (r *OAuthStateRepository) SaveState(state string, data *carwise.OAuthState, ttl time.Duration) error {
+	key := fmt.Sprintf("oauth-state:%s", state)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "provider", data.Provider, "code_verifier", data.CodeVerifier, "nonce", data.Nonce)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save oauth state to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *OAuthStateRepository) ConsumeState(state string) (*carwise.OAuthState, error) {
+	key := fmt.Sprintf("oauth-state:%s", state)
+	ctx := context.Background()
+
+	var values *redis.MapStringStringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume oauth state from Redis: %v", err)
+	}
+
+	fields := values.Val()
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return &carwise.OAuthState{
+		Provider:     fields["provider"],
+		CodeVerifier: fields["code_verifier"],
+		Nonce:        fields["nonce"],
+	}, nil
+}
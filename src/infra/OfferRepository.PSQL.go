@@ -0,0 +1,275 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const offerColumns = `
+			o.id, 
+			o.car_id, 
+			o.buyer_id, 
+			o.seller_id, 
+			o.amount, 
+			o.currency, 
+			COALESCE(o.trade_in_car_id, ''), 
+			o.message, 
+			o.status, 
+			o.expires_at, 
+			o.created_at, 
+			o.updated_at`
+
+var openOfferStatuses = []string{carwise.OfferStatusPending, carwise.OfferStatusCountered}
+
+type OfferRepository struct {
+	db *sql.DB
+}
+
+func NewOfferRepository() *OfferRepository {
+	database := ConnectDb()
+	return &OfferRepository{db: database}
+}
+
+func (r *OfferRepository) Create(offer *carwise.Offer) error {
+	query := `
+		INSERT INTO offers (
+			id, 
+			car_id, 
+			buyer_id, 
+			seller_id, 
+			amount, 
+			currency, 
+			trade_in_car_id, 
+			message, 
+			status, 
+			expires_at, 
+			created_at, 
+			updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, $12
+		)`
+	_, err := r.db.Exec(query,
+		offer.ID,
+		offer.CarID,
+		offer.BuyerID,
+		offer.SellerID,
+		offer.Amount,
+		offer.Currency,
+		offer.TradeInCarID,
+		offer.Message,
+		offer.Status,
+		offer.ExpiresAt,
+		offer.CreatedAt,
+		offer.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create offer: %w", err)
+	}
+	return nil
+}
+
+// Update saves the offer only if it still has expectedStatus, so two
+// concurrent responses cannot both move it on; the loser gets
+// carwise.ErrOfferConflict.
+func (r *OfferRepository) Update(offer *carwise.Offer, expectedStatus string) error {
+	return updateOffer(r.db, offer, expectedStatus)
+}
+
+// Accept accepts the offer, marks the car sold and rejects the other open
+// offers on it in one transaction, and returns the rejected offers. It fails
+// with carwise.ErrCarNotAvailable when the car is no longer active.
+func (r *OfferRepository) Accept(offer *carwise.Offer, expectedStatus string) ([]carwise.Offer, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateOffer(tx, offer, expectedStatus); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.Exec(`UPDATE cars SET status = $1 WHERE id = $2 AND status = $3`, carwise.CarStatusSold, offer.CarID, carwise.CarStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark car as sold: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark car as sold: %w", err)
+	}
+	if affected == 0 {
+		return nil, carwise.ErrCarNotAvailable
+	}
+
+	query := `
+		UPDATE offers o
+		SET 
+			status = $1, 
+			updated_at = $2 
+		WHERE o.car_id = $3 AND o.id <> $4 AND o.status = ANY($5)
+		RETURNING ` + offerColumns
+	closed, err := queryOffers(tx, query, carwise.OfferStatusRejected, offer.UpdatedAt, offer.CarID, offer.ID, pq.Array(openOfferStatuses))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit offer: %w", err)
+	}
+	return closed, nil
+}
+
+func (r *OfferRepository) GetByID(id string) (*carwise.Offer, error) {
+	query := `
+		SELECT ` + offerColumns + `
+		FROM offers o
+		WHERE o.id = $1
+	`
+	offer, err := scanOffer(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch offer: %w", err)
+	}
+	return &offer, nil
+}
+
+func (r *OfferRepository) GetOpenByCarAndBuyer(carID, buyerID string) (*carwise.Offer, error) {
+	query := `
+		SELECT ` + offerColumns + `
+		FROM offers o
+		WHERE o.car_id = $1 AND o.buyer_id = $2 AND o.status = ANY($3)
+	`
+	offer, err := scanOffer(r.db.QueryRow(query, carID, buyerID, pq.Array(openOfferStatuses)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch offer: %w", err)
+	}
+	return &offer, nil
+}
+
+func (r *OfferRepository) GetByUser(userID, role, status string, page, limit int) ([]carwise.Offer, error) {
+	offset := (page - 1) * limit
+
+	query := `
+		SELECT ` + offerColumns + `
+		FROM offers o
+	`
+	var conditions []string
+	args := []interface{}{userID}
+
+	switch role {
+	case carwise.ParticipantRoleBuyer:
+		conditions = append(conditions, "o.buyer_id = $1")
+	case carwise.ParticipantRoleSeller:
+		conditions = append(conditions, "o.seller_id = $1")
+	default:
+		conditions = append(conditions, "(o.buyer_id = $1 OR o.seller_id = $1)")
+	}
+
+	if status != "" {
+		conditions = append(conditions, "o.status = $"+fmt.Sprint(len(args)+1))
+		args = append(args, status)
+	}
+
+	query += " WHERE " + strings.Join(conditions, " AND ")
+	query += " ORDER BY o.updated_at DESC LIMIT $" + fmt.Sprint(len(args)+1) + " OFFSET $" + fmt.Sprint(len(args)+2)
+	args = append(args, limit, offset)
+
+	return r.query(query, args...)
+}
+
+func (r *OfferRepository) GetExpired(before time.Time) ([]carwise.Offer, error) {
+	query := `
+		SELECT ` + offerColumns + `
+		FROM offers o
+		WHERE o.status = ANY($1) AND o.expires_at <= $2
+		ORDER BY o.expires_at
+	`
+	return r.query(query, pq.Array(openOfferStatuses), before)
+}
+
+func (r *OfferRepository) query(query string, args ...interface{}) ([]carwise.Offer, error) {
+	return queryOffers(r.db, query, args...)
+}
+
+// offerExecutor is satisfied by both *sql.DB and *sql.Tx.
+type offerExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func updateOffer(db offerExecutor, offer *carwise.Offer, expectedStatus string) error {
+	query := `
+		UPDATE offers 
+		SET 
+			amount = $1, 
+			message = $2, 
+			status = $3, 
+			expires_at = $4, 
+			updated_at = $5 
+		WHERE id = $6 AND status = $7`
+	result, err := db.Exec(query, offer.Amount, offer.Message, offer.Status, offer.ExpiresAt, offer.UpdatedAt, offer.ID, expectedStatus)
+	if err != nil {
+		return fmt.Errorf("failed to update offer: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update offer: %w", err)
+	}
+	if affected == 0 {
+		return carwise.ErrOfferConflict
+	}
+	return nil
+}
+
+func queryOffers(db offerExecutor, query string, args ...interface{}) ([]carwise.Offer, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch offers: %w", err)
+	}
+	defer rows.Close()
+
+	var offers []carwise.Offer
+	for rows.Next() {
+		offer, err := scanOffer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan offer: %w", err)
+		}
+		offers = append(offers, offer)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return offers, nil
+}
+
+func scanOffer(row rowScanner) (carwise.Offer, error) {
+	var offer carwise.Offer
+	err := row.Scan(
+		&offer.ID,
+		&offer.CarID,
+		&offer.BuyerID,
+		&offer.SellerID,
+		&offer.Amount,
+		&offer.Currency,
+		&offer.TradeInCarID,
+		&offer.Message,
+		&offer.Status,
+		&offer.ExpiresAt,
+		&offer.CreatedAt,
+		&offer.UpdatedAt,
+	)
+	return offer, err
+}
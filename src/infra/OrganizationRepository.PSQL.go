@@ -0,0 +1,530 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const organizationColumns = `
+			id, 
+			name, 
+			tax_number, 
+			tax_office, 
+			address, 
+			city, 
+			country_code, 
+			phone_number, 
+			logo_url, 
+			verified, 
+			verified_at, 
+			created_at, 
+			updated_at`
+
+const organizationInvitationColumns = `
+			id, 
+			organization_id, 
+			email, 
+			role, 
+			invited_by, 
+			created_at, 
+			expires_at`
+
+type OrganizationRepository struct {
+	db *sql.DB
+}
+
+func NewOrganizationRepository() *OrganizationRepository {
+	database := ConnectDb()
+	return &OrganizationRepository{db: database}
+}
+
+func (r *OrganizationRepository) Create(organization *carwise.Organization, owner *carwise.OrganizationMember) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO organizations (
+			id, 
+			name, 
+			tax_number, 
+			tax_office, 
+			address, 
+			city, 
+			country_code, 
+			phone_number, 
+			logo_url, 
+			created_at, 
+			updated_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)`
+	_, err = tx.Exec(query,
+		organization.ID,
+		organization.Name,
+		organization.TaxNumber,
+		organization.TaxOffice,
+		organization.Address,
+		organization.City,
+		organization.CountryCode,
+		organization.PhoneNumber,
+		organization.LogoUrl,
+		organization.CreatedAt,
+		organization.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO organization_members (organization_id, user_id, role, created_at) VALUES ($1, $2, $3, $4)`,
+		owner.OrganizationID, owner.UserID, owner.Role, owner.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add organization owner: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit organization: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OrganizationRepository) GetByID(id string) (*carwise.Organization, error) {
+	query := `
+		SELECT ` + organizationColumns + `
+		FROM organizations
+		WHERE id = $1
+	`
+	organization, err := scanOrganization(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch organization: %w", err)
+	}
+	return organization, nil
+}
+
+func (r *OrganizationRepository) Update(organization *carwise.Organization) error {
+	query := `
+		UPDATE organizations 
+		SET 
+			name = $1, 
+			tax_number = $2, 
+			tax_office = $3, 
+			address = $4, 
+			city = $5, 
+			country_code = $6, 
+			phone_number = $7, 
+			logo_url = $8, 
+			verified = $9, 
+			verified_at = $10, 
+			updated_at = NOW() 
+		WHERE id = $11`
+
+	_, err := r.db.Exec(query,
+		organization.Name,
+		organization.TaxNumber,
+		organization.TaxOffice,
+		organization.Address,
+		organization.City,
+		organization.CountryCode,
+		organization.PhoneNumber,
+		organization.LogoUrl,
+		organization.Verified,
+		sql.NullTime{Time: organization.VerifiedAt, Valid: organization.Verified},
+		organization.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) SetVerified(id string, verified bool, at time.Time) error {
+	query := `
+		UPDATE organizations 
+		SET 
+			verified = $1, 
+			verified_at = $2, 
+			updated_at = NOW() 
+		WHERE id = $3`
+
+	_, err := r.db.Exec(query, verified, sql.NullTime{Time: at, Valid: verified}, id)
+	if err != nil {
+		return fmt.Errorf("failed to update organization verification: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) Search(query, verification string, page, limit int) ([]carwise.Organization, error) {
+	offset := (page - 1) * limit
+
+	sqlQuery := `
+		SELECT ` + organizationColumns + `
+		FROM organizations
+	`
+	conditions := []string{}
+	args := []interface{}{}
+
+	if query != "" {
+		placeholder := "$" + fmt.Sprint(len(args)+1)
+		conditions = append(conditions, "(name ILIKE "+placeholder+" OR tax_number ILIKE "+placeholder+")")
+		args = append(args, "%"+query+"%")
+	}
+	switch verification {
+	case carwise.OrganizationVerificationVerified:
+		conditions = append(conditions, "verified = TRUE")
+	case carwise.OrganizationVerificationPending:
+		conditions = append(conditions, "verified = FALSE")
+	}
+
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sqlQuery += " ORDER BY created_at DESC LIMIT $" + fmt.Sprint(len(args)+1) + " OFFSET $" + fmt.Sprint(len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search organizations: %w", err)
+	}
+	defer rows.Close()
+
+	var organizations []carwise.Organization
+	for rows.Next() {
+		organization, err := scanOrganization(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization: %w", err)
+		}
+		organizations = append(organizations, *organization)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return organizations, nil
+}
+
+func (r *OrganizationRepository) CountActiveListings(id string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM cars WHERE organization_id = $1 AND status = $2`, id, carwise.CarStatusActive).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count organization listings: %w", err)
+	}
+	return count, nil
+}
+
+func (r *OrganizationRepository) GetMember(organizationID, userID string) (*carwise.OrganizationMember, error) {
+	query := `
+		SELECT 
+			organization_id, 
+			user_id, 
+			role, 
+			created_at 
+		FROM organization_members 
+		WHERE organization_id = $1 AND user_id = $2`
+
+	member, err := scanOrganizationMember(r.db.QueryRow(query, organizationID, userID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch organization member: %w", err)
+	}
+	return &member, nil
+}
+
+func (r *OrganizationRepository) GetMembers(organizationID string) ([]carwise.OrganizationMember, error) {
+	query := `
+		SELECT 
+			organization_id, 
+			user_id, 
+			role, 
+			created_at 
+		FROM organization_members 
+		WHERE organization_id = $1 
+		ORDER BY created_at`
+
+	return r.queryMembers(query, organizationID)
+}
+
+func (r *OrganizationRepository) GetMemberships(userID string) ([]carwise.OrganizationMember, error) {
+	query := `
+		SELECT 
+			organization_id, 
+			user_id, 
+			role, 
+			created_at 
+		FROM organization_members 
+		WHERE user_id = $1 
+		ORDER BY created_at`
+
+	return r.queryMembers(query, userID)
+}
+
+func (r *OrganizationRepository) UpdateMemberRole(organizationID, userID, role string) error {
+	return r.changeMembers(organizationID, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`UPDATE organization_members SET role = $1 WHERE organization_id = $2 AND user_id = $3`, role, organizationID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to update organization member: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *OrganizationRepository) RemoveMember(organizationID, userID string) error {
+	return r.changeMembers(organizationID, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`, organizationID, userID)
+		if err != nil {
+			return fmt.Errorf("failed to remove organization member: %w", err)
+		}
+		return nil
+	})
+}
+
+// changeMembers applies a membership change while holding a lock on the
+// organization row, so concurrent changes cannot leave it without an owner.
+func (r *OrganizationRepository) changeMembers(organizationID string, change func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRow(`SELECT id FROM organizations WHERE id = $1 FOR UPDATE`, organizationID).Scan(&id)
+	if err != nil {
+		return fmt.Errorf("failed to lock organization: %w", err)
+	}
+
+	if err := change(tx); err != nil {
+		return err
+	}
+
+	var owners int
+	err = tx.QueryRow(`SELECT COUNT(*) FROM organization_members WHERE organization_id = $1 AND role = $2`, organizationID, carwise.OrganizationRoleOwner).Scan(&owners)
+	if err != nil {
+		return fmt.Errorf("failed to count organization owners: %w", err)
+	}
+	if owners == 0 {
+		return carwise.ErrLastOrganizationOwner
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit organization members: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) SaveInvitation(invitation *carwise.OrganizationInvitation) error {
+	query := `
+		INSERT INTO organization_invitations (
+			id, 
+			organization_id, 
+			email, 
+			role, 
+			invited_by, 
+			created_at, 
+			expires_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		) 
+		ON CONFLICT (organization_id, email) DO UPDATE SET 
+			role = EXCLUDED.role, 
+			invited_by = EXCLUDED.invited_by, 
+			created_at = EXCLUDED.created_at, 
+			expires_at = EXCLUDED.expires_at 
+		RETURNING id`
+
+	err := r.db.QueryRow(query,
+		invitation.ID,
+		invitation.OrganizationID,
+		invitation.Email,
+		invitation.Role,
+		invitation.InvitedBy,
+		invitation.CreatedAt,
+		invitation.ExpiresAt,
+	).Scan(&invitation.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save organization invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) GetInvitation(id string) (*carwise.OrganizationInvitation, error) {
+	query := `
+		SELECT ` + organizationInvitationColumns + `
+		FROM organization_invitations 
+		WHERE id = $1`
+
+	invitation, err := scanOrganizationInvitation(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch organization invitation: %w", err)
+	}
+	return &invitation, nil
+}
+
+func (r *OrganizationRepository) GetInvitations(organizationID string, now time.Time) ([]carwise.OrganizationInvitation, error) {
+	query := `
+		SELECT ` + organizationInvitationColumns + `
+		FROM organization_invitations 
+		WHERE organization_id = $1 AND expires_at > $2 
+		ORDER BY created_at DESC`
+
+	return r.queryInvitations(query, organizationID, now)
+}
+
+func (r *OrganizationRepository) GetInvitationsByEmail(email string, now time.Time) ([]carwise.OrganizationInvitation, error) {
+	query := `
+		SELECT ` + organizationInvitationColumns + `
+		FROM organization_invitations 
+		WHERE email = LOWER($1) AND expires_at > $2 
+		ORDER BY created_at DESC`
+
+	return r.queryInvitations(query, email, now)
+}
+
+// AcceptInvitation turns a pending invitation into a membership. It returns
+// carwise.ErrInvitationNotFound when the invitation is gone or has expired.
+func (r *OrganizationRepository) AcceptInvitation(id, userID string, at time.Time) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var organizationID, role string
+	err = tx.QueryRow(`DELETE FROM organization_invitations WHERE id = $1 AND expires_at > $2 RETURNING organization_id, role`, id, at).Scan(&organizationID, &role)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return carwise.ErrInvitationNotFound
+		}
+		return fmt.Errorf("failed to claim organization invitation: %w", err)
+	}
+
+	_, err = tx.Exec(`INSERT INTO organization_members (organization_id, user_id, role, created_at) VALUES ($1, $2, $3, $4) ON CONFLICT (organization_id, user_id) DO NOTHING`,
+		organizationID, userID, role, at)
+	if err != nil {
+		return fmt.Errorf("failed to add organization member: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit organization invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) DeleteInvitation(id string) error {
+	_, err := r.db.Exec(`DELETE FROM organization_invitations WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete organization invitation: %w", err)
+	}
+	return nil
+}
+
+func (r *OrganizationRepository) queryInvitations(query string, args ...interface{}) ([]carwise.OrganizationInvitation, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organization invitations: %w", err)
+	}
+	defer rows.Close()
+
+	var invitations []carwise.OrganizationInvitation
+	for rows.Next() {
+		invitation, err := scanOrganizationInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (r *OrganizationRepository) queryMembers(query string, args ...interface{}) ([]carwise.OrganizationMember, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch organization members: %w", err)
+	}
+	defer rows.Close()
+
+	var members []carwise.OrganizationMember
+	for rows.Next() {
+		member, err := scanOrganizationMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization member: %w", err)
+		}
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return members, nil
+}
+
+func scanOrganization(row rowScanner) (*carwise.Organization, error) {
+	organization := &carwise.Organization{}
+	var verifiedAt sql.NullTime
+	err := row.Scan(
+		&organization.ID,
+		&organization.Name,
+		&organization.TaxNumber,
+		&organization.TaxOffice,
+		&organization.Address,
+		&organization.City,
+		&organization.CountryCode,
+		&organization.PhoneNumber,
+		&organization.LogoUrl,
+		&organization.Verified,
+		&verifiedAt,
+		&organization.CreatedAt,
+		&organization.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	organization.VerifiedAt = verifiedAt.Time
+	return organization, nil
+}
+
+func scanOrganizationMember(row rowScanner) (carwise.OrganizationMember, error) {
+	var member carwise.OrganizationMember
+	err := row.Scan(
+		&member.OrganizationID,
+		&member.UserID,
+		&member.Role,
+		&member.CreatedAt,
+	)
+	return member, err
+}
+
+func scanOrganizationInvitation(row rowScanner) (carwise.OrganizationInvitation, error) {
+	var invitation carwise.OrganizationInvitation
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.OrganizationID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.InvitedBy,
+		&invitation.CreatedAt,
+		&invitation.ExpiresAt,
+	)
+	return invitation, err
+}
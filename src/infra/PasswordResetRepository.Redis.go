@@ -8,6 +8,14 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
+var consumeResetCodeScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1], KEYS[2])
+	return 1
+end
+return 0
+`)
+
 type PasswordResetRepository struct {
 	client *redis.Client
 }
@@ -16,9 +24,16 @@ func NewPasswordResetRepository() *PasswordResetRepository {
 	return &PasswordResetRepository{client: ConnectRedis()}
 }
 
-func (r *PasswordResetRepository) SaveResetCode(email, code string, ttl time.Duration) error {
+func (r *PasswordResetRepository) SaveResetCode(email, codeHash string, ttl time.Duration) error {
 	key := fmt.Sprintf("password-reset:%s", email)
-	err := r.client.Set(context.Background(), key, code, ttl).Err()
+	attemptsKey := fmt.Sprintf("password-reset-attempts:%s", email)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, codeHash, ttl)
+		pipe.Del(ctx, attemptsKey)
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("failed to save password reset code to Redis: %v", err)
 	}
@@ -26,22 +41,40 @@ func (r *PasswordResetRepository) SaveResetCode(email, code string, ttl time.Dur
 	return nil
 }
 
-func (r *PasswordResetRepository) VerifyResetCode(email, code string) (bool, error) {
+// ConsumeResetCode deletes the code if it matches, in one step, so the same
+// reset link cannot be used by two requests at once.
+func (r *PasswordResetRepository) ConsumeResetCode(email, codeHash string) (bool, error) {
 	key := fmt.Sprintf("password-reset:%s", email)
-	storedCode, err := r.client.Get(context.Background(), key).Result()
-	if err == redis.Nil {
-		return false, nil
+	attemptsKey := fmt.Sprintf("password-reset-attempts:%s", email)
+	consumed, err := consumeResetCodeScript.Run(context.Background(), r.client, []string{key, attemptsKey}, codeHash).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to consume password reset code in Redis: %v", err)
 	}
+
+	return consumed == 1, nil
+}
+
+func (r *PasswordResetRepository) IncrementAttempts(email string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("password-reset-attempts:%s", email)
+	attempts, err := r.client.Incr(context.Background(), key).Result()
 	if err != nil {
-		return false, fmt.Errorf("failed to retrieve password reset code from Redis: %v", err)
+		return 0, fmt.Errorf("failed to increment password reset attempts in Redis: %v", err)
+	}
+
+	if attempts == 1 {
+		err = r.client.Expire(context.Background(), key, window).Err()
+		if err != nil {
+			return 0, fmt.Errorf("failed to set password reset attempts window in Redis: %v", err)
+		}
 	}
 
-	return storedCode == code, nil
+	return attempts, nil
 }
 
 func (r *PasswordResetRepository) DeleteResetCode(email string) error {
 	key := fmt.Sprintf("password-reset:%s", email)
-	err := r.client.Del(context.Background(), key).Err()
+	attemptsKey := fmt.Sprintf("password-reset-attempts:%s", email)
+	err := r.client.Del(context.Background(), key, attemptsKey).Err()
 	if err != nil {
 		return fmt.Errorf("failed to delete password reset code from Redis: %v", err)
 	}
@@ -0,0 +1,51 @@
+package infra
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PhoneRelayGateway hands out extensions on a shared relay number. The telephony
+// provider looks up phone-relay:<extension> to forward the call to the seller.
+type PhoneRelayGateway struct {
+	client *redis.Client
+	number string
+}
+
+func NewPhoneRelayGateway() *PhoneRelayGateway {
+	return &PhoneRelayGateway{
+		client: ConnectRedis(),
+		number: strings.TrimSpace(os.Getenv("PHONE_RELAY_NUMBER")),
+	}
+}
+
+func (gw *PhoneRelayGateway) Allocate(phoneNumber, carID, viewerID string, ttl time.Duration) (string, string, error) {
+	ctx := context.Background()
+
+	for attempt := 0; attempt < 5; attempt++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+		if err != nil {
+			return "", "", err
+		}
+		extension := fmt.Sprintf("%06d", n.Int64())
+		key := fmt.Sprintf("phone-relay:%s", extension)
+
+		ok, err := gw.client.SetNX(ctx, key, phoneNumber+"|"+carID+"|"+viewerID, ttl).Result()
+		if err != nil {
+			return "", "", fmt.Errorf("failed to allocate relay extension: %v", err)
+		}
+		if ok {
+			return gw.number, extension, nil
+		}
+	}
+
+	return "", "", errors.New("failed to allocate relay extension: no free extension found")
+}
@@ -0,0 +1,95 @@
+package infra
+
+import (
+	"carwise"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type PhoneVerificationRepository struct {
+	client *redis.Client
+}
+
+func NewPhoneVerificationRepository() *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{client: ConnectRedis()}
+}
+
+func (r *PhoneVerificationRepository) SaveOTP(userID string, otp *carwise.PhoneOTP, ttl time.Duration) error {
+	key := fmt.Sprintf("phone-verification:%s", userID)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, "phone", otp.Phone, "code_hash", otp.CodeHash, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save phone verification code to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PhoneVerificationRepository) GetOTP(userID string) (*carwise.PhoneOTP, error) {
+	key := fmt.Sprintf("phone-verification:%s", userID)
+	values, err := r.client.HGetAll(context.Background(), key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve phone verification code from Redis: %v", err)
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	attempts, err := strconv.Atoi(values["attempts"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone verification attempt counter: %v", err)
+	}
+
+	return &carwise.PhoneOTP{
+		Phone:    values["phone"],
+		CodeHash: values["code_hash"],
+		Attempts: attempts,
+	}, nil
+}
+
+func (r *PhoneVerificationRepository) IncrementOTPAttempts(userID string) (int64, error) {
+	key := fmt.Sprintf("phone-verification:%s", userID)
+	attempts, err := r.client.HIncrBy(context.Background(), key, "attempts", 1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment phone verification attempts in Redis: %v", err)
+	}
+
+	return attempts, nil
+}
+
+func (r *PhoneVerificationRepository) DeleteOTP(userID string) error {
+	key := fmt.Sprintf("phone-verification:%s", userID)
+	err := r.client.Del(context.Background(), key).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete phone verification code from Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *PhoneVerificationRepository) IncrementSendCount(userID string, window time.Duration) (int64, error) {
+	key := fmt.Sprintf("phone-verification-send:%s", userID)
+	count, err := r.client.Incr(context.Background(), key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment phone verification send count in Redis: %v", err)
+	}
+
+	if count == 1 {
+		err = r.client.Expire(context.Background(), key, window).Err()
+		if err != nil {
+			return 0, fmt.Errorf("failed to set phone verification send window in Redis: %v", err)
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,84 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+)
+
+type PriceHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewPriceHistoryRepository() *PriceHistoryRepository {
+	database := ConnectDb()
+	return &PriceHistoryRepository{db: database}
+}
+
+func (r *PriceHistoryRepository) Create(change *carwise.PriceChange) error {
+	query := `
+		INSERT INTO car_price_history (
+			car_id, 
+			old_price, 
+			old_currency, 
+			new_price, 
+			new_currency, 
+			changed_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)`
+	_, err := r.db.Exec(query,
+		change.CarID,
+		change.OldPrice,
+		change.OldCurrency,
+		change.NewPrice,
+		change.NewCurrency,
+		change.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record price change: %w", err)
+	}
+	return nil
+}
+
+func (r *PriceHistoryRepository) GetByCar(carID string) ([]carwise.PriceChange, error) {
+	query := `
+		SELECT 
+			car_id, 
+			old_price, 
+			old_currency, 
+			new_price, 
+			new_currency, 
+			changed_at 
+		FROM car_price_history 
+		WHERE car_id = $1 
+		ORDER BY changed_at`
+
+	rows, err := r.db.Query(query, carID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch price history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []carwise.PriceChange
+	for rows.Next() {
+		var change carwise.PriceChange
+		if err := rows.Scan(
+			&change.CarID,
+			&change.OldPrice,
+			&change.OldCurrency,
+			&change.NewPrice,
+			&change.NewCurrency,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan price change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return changes, nil
+}
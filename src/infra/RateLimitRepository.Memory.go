@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"carwise"
+	"sync"
+	"time"
+)
+
+const memoryRateLimitSweepInterval = time.Minute
+
+type memoryRateLimitBucket struct {
+	hits   []time.Time
+	window time.Duration
+}
+
+type MemoryRateLimitRepository struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryRateLimitBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitRepository() *MemoryRateLimitRepository {
+	return &MemoryRateLimitRepository{
+		buckets:   make(map[string]*memoryRateLimitBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (r *MemoryRateLimitRepository) Allow(key string, limit int, window time.Duration) (*carwise.RateLimitResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.sweep(now)
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &memoryRateLimitBucket{}
+		r.buckets[key] = bucket
+	}
+	bucket.window = window
+
+	for len(bucket.hits) > 0 && !bucket.hits[0].After(now.Add(-window)) {
+		bucket.hits = bucket.hits[1:]
+	}
+
+	allowed := len(bucket.hits) < limit
+	if allowed {
+		bucket.hits = append(bucket.hits, now)
+	}
+
+	reset := window
+	if len(bucket.hits) > 0 {
+		reset = bucket.hits[0].Add(window).Sub(now)
+	}
+
+	return &carwise.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: limit - len(bucket.hits),
+		Reset:     reset,
+	}, nil
+}
+
+func (r *MemoryRateLimitRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < memoryRateLimitSweepInterval {
+		return
+	}
+	r.lastSweep = now
+
+	for key, bucket := range r.buckets {
+		if len(bucket.hits) == 0 || now.Sub(bucket.hits[len(bucket.hits)-1]) > bucket.window {
+			delete(r.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package infra
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitRepositoryAllow(t *testing.T) {
+	repo := NewMemoryRateLimitRepository()
+
+	for n := 1; n <= 3; n++ {
+		result, err := repo.Allow("login:ip:1.2.3.4", 3, time.Minute)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Allowed {
+			t.Fatalf("request %d: expected to be allowed", n)
+		}
+		if result.Limit != 3 || result.Remaining != 3-n {
+			t.Errorf("request %d: got limit %d remaining %d", n, result.Limit, result.Remaining)
+		}
+		if result.Reset <= 0 || result.Reset > time.Minute {
+			t.Errorf("request %d: got reset %v", n, result.Reset)
+		}
+	}
+
+	result, err := repo.Allow("login:ip:1.2.3.4", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Allowed || result.Remaining != 0 {
+		t.Errorf("expected the fourth request to be rejected, got allowed=%v remaining=%d", result.Allowed, result.Remaining)
+	}
+
+	result, err = repo.Allow("login:ip:5.6.7.8", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Allowed || result.Remaining != 2 {
+		t.Errorf("expected other keys to have their own budget, got allowed=%v remaining=%d", result.Allowed, result.Remaining)
+	}
+}
+
+func TestMemoryRateLimitRepositoryWindow(t *testing.T) {
+	repo := NewMemoryRateLimitRepository()
+	window := 50 * time.Millisecond
+
+	for n := 0; n < 2; n++ {
+		if result, _ := repo.Allow("key", 2, window); !result.Allowed {
+			t.Fatalf("request %d: expected to be allowed", n)
+		}
+	}
+	if result, _ := repo.Allow("key", 2, window); result.Allowed {
+		t.Fatal("expected the request over the limit to be rejected")
+	}
+
+	time.Sleep(window + 10*time.Millisecond)
+
+	result, _ := repo.Allow("key", 2, window)
+	if !result.Allowed || result.Remaining != 1 {
+		t.Errorf("expected the window to slide, got allowed=%v remaining=%d", result.Allowed, result.Remaining)
+	}
+}
+
+func TestMemoryRateLimitRepositorySweep(t *testing.T) {
+	repo := NewMemoryRateLimitRepository()
+	repo.Allow("stale", 1, time.Millisecond)
+	repo.Allow("fresh", 1, time.Hour)
+
+	repo.sweep(time.Now().Add(memoryRateLimitSweepInterval + time.Second))
+
+	if _, ok := repo.buckets["stale"]; ok {
+		t.Error("expected the expired bucket to be swept")
+	}
+	if _, ok := repo.buckets["fresh"]; !ok {
+		t.Error("expected the active bucket to be kept")
+	}
+}
@@ -0,0 +1,74 @@
+package infra
+
+import (
+	"carwise"
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+type RateLimitRepository struct {
+	client   *redis.Client
+	fallback *MemoryRateLimitRepository
+}
+
+func NewRateLimitRepository() *RateLimitRepository {
+	return &RateLimitRepository{
+		client:   ConnectRedis(),
+		fallback: NewMemoryRateLimitRepository(),
+	}
+}
+
+func (r *RateLimitRepository) Allow(key string, limit int, window time.Duration) (*carwise.RateLimitResult, error) {
+	redisKey := fmt.Sprintf("rate-limit:%s", key)
+	now := time.Now().UnixMilli()
+
+	values, err := slidingWindowScript.Run(context.Background(), r.client, []string{redisKey},
+		now, window.Milliseconds(), limit, fmt.Sprintf("%d-%d", now, rand.Int63()),
+	).Int64Slice()
+	if err != nil {
+		log.Printf("rate limiter falling back to memory: %v", err)
+		return r.fallback.Allow(key, limit, window)
+	}
+
+	remaining := limit - int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &carwise.RateLimitResult{
+		Allowed:   values[0] == 1,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
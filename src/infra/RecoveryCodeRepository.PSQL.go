@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+type RecoveryCodeRepository struct {
+	db *sql.DB
+}
+
+func NewRecoveryCodeRepository() *RecoveryCodeRepository {
+	database := ConnectDb()
+	return &RecoveryCodeRepository{db: database}
+}
+
+func (r *RecoveryCodeRepository) ReplaceRecoveryCodes(userID string, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, codeHash := range codeHashes {
+		_, err = tx.Exec(`
+			INSERT INTO user_recovery_codes (
+				user_id, 
+				code_hash
+			) VALUES (
+				$1, $2
+			)`, userID, codeHash)
+		if err != nil {
+			return fmt.Errorf("failed to save recovery code: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit recovery codes: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RecoveryCodeRepository) UseRecoveryCode(userID, codeHash string) (bool, error) {
+	query := `
+		UPDATE user_recovery_codes 
+		SET 
+			used_at = NOW() 
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	result, err := r.db.Exec(query, userID, codeHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+func (r *RecoveryCodeRepository) DeleteRecoveryCodes(userID string) error {
+	_, err := r.db.Exec(`DELETE FROM user_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package infra
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+type LogSMSGateway struct {
+	FilePath string
+	mu       sync.Mutex
+}
+
+func NewLogSMSGateway() *LogSMSGateway {
+	return &LogSMSGateway{
+		FilePath: os.Getenv("SMS_LOG_FILE"),
+	}
+}
+
+func (GW *LogSMSGateway) Send(To string, Body string) error {
+	log.Printf("SMS to %s: %s", To, Body)
+
+	if GW.FilePath == "" {
+		return nil
+	}
+
+	GW.mu.Lock()
+	defer GW.mu.Unlock()
+
+	f, err := os.OpenFile(GW.FilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), To, Body)
+	return err
+}
@@ -0,0 +1,65 @@
+package infra
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// TwilioSMSGateway sends text messages through the Twilio Messages API.
+type TwilioSMSGateway struct {
+	APIURL     string
+	AccountSID string
+	AuthToken  string
+	From       string
+	client     *http.Client
+}
+
+func NewTwilioSMSGateway() *TwilioSMSGateway {
+	return &TwilioSMSGateway{
+		APIURL:     twilioAPIURL,
+		AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		From:       os.Getenv("TWILIO_FROM_NUMBER"),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Configured reports whether the account credentials and sender number are
+// all set.
+func (GW *TwilioSMSGateway) Configured() bool {
+	return GW.AccountSID != "" && GW.AuthToken != "" && GW.From != ""
+}
+
+func (GW *TwilioSMSGateway) Send(To string, Body string) error {
+	form := url.Values{}
+	form.Set("To", To)
+	form.Set("From", GW.From)
+	form.Set("Body", Body)
+
+	endpoint := fmt.Sprintf("%s/Accounts/%s/Messages.json", GW.APIURL, url.PathEscape(GW.AccountSID))
+	request, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to build SMS request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.SetBasicAuth(GW.AccountSID, GW.AuthToken)
+
+	response, err := GW.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send SMS: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 512))
+		return fmt.Errorf("failed to send SMS: status %d: %s", response.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package infra
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTwilioSMSGatewaySend(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "created", status: http.StatusCreated},
+		{name: "rejected", status: http.StatusBadRequest, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/Accounts/AC123/Messages.json" {
+					t.Errorf("got %s %s", r.Method, r.URL.Path)
+				}
+				if user, password, ok := r.BasicAuth(); !ok || user != "AC123" || password != "secret" {
+					t.Errorf("got basic auth %q %q", user, password)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Fatalf("ParseForm: %v", err)
+				}
+				for key, want := range map[string]string{"To": "+905321234567", "From": "+15005550006", "Body": "Your code is 123456"} {
+					if got := r.PostForm.Get(key); got != want {
+						t.Errorf("%s is %q, want %q", key, got, want)
+					}
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message":"invalid number"}`))
+			}))
+			defer server.Close()
+
+			gateway := &TwilioSMSGateway{
+				APIURL:     server.URL,
+				AccountSID: "AC123",
+				AuthToken:  "secret",
+				From:       "+15005550006",
+				client:     server.Client(),
+			}
+			err := gateway.Send("+905321234567", "Your code is 123456")
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "invalid number") {
+					t.Fatalf("got error %v, want the response detail", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send: %v", err)
+			}
+		})
+	}
+}
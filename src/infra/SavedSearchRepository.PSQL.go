@@ -0,0 +1,229 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const savedSearchColumns = `
+			s.id, 
+			s.user_id, 
+			s.name, 
+			s.brand_id, 
+			s.series_id, 
+			s.model_id, 
+			s.frequency, 
+			s.unsubscribe_token, 
+			s.last_run_at, 
+			s.created_at`
+
+type SavedSearchRepository struct {
+	db *sql.DB
+}
+
+func NewSavedSearchRepository() *SavedSearchRepository {
+	database := ConnectDb()
+	return &SavedSearchRepository{db: database}
+}
+
+func (r *SavedSearchRepository) Create(search *carwise.SavedSearch) error {
+	query := `
+		INSERT INTO saved_searches (
+			id, 
+			user_id, 
+			name, 
+			brand_id, 
+			series_id, 
+			model_id, 
+			frequency, 
+			unsubscribe_token, 
+			last_run_at, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+		)`
+	_, err := r.db.Exec(query,
+		search.ID,
+		search.UserID,
+		search.Name,
+		search.BrandID,
+		search.SeriesID,
+		search.ModelID,
+		search.Frequency,
+		search.UnsubscribeToken,
+		search.LastRunAt,
+		search.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepository) Update(search *carwise.SavedSearch) error {
+	query := `
+		UPDATE saved_searches 
+		SET 
+			name = $1, 
+			brand_id = $2, 
+			series_id = $3, 
+			model_id = $4, 
+			frequency = $5, 
+			last_run_at = $6 
+		WHERE id = $7`
+
+	_, err := r.db.Exec(query,
+		search.Name,
+		search.BrandID,
+		search.SeriesID,
+		search.ModelID,
+		search.Frequency,
+		search.LastRunAt,
+		search.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM saved_searches WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepository) GetByID(id string) (*carwise.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches s
+		WHERE s.id = $1
+	`
+	search, err := scanSavedSearch(r.db.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch saved search: %w", err)
+	}
+	return &search, nil
+}
+
+func (r *SavedSearchRepository) GetByUnsubscribeToken(token string) (*carwise.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches s
+		WHERE s.unsubscribe_token = $1
+	`
+	search, err := scanSavedSearch(r.db.QueryRow(query, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch saved search: %w", err)
+	}
+	return &search, nil
+}
+
+func (r *SavedSearchRepository) GetByUser(userID string) ([]carwise.SavedSearch, error) {
+	query := `
+		SELECT ` + savedSearchColumns + `
+		FROM saved_searches s
+		WHERE s.user_id = $1
+		ORDER BY s.created_at DESC
+	`
+	return r.query(query, userID)
+}
+
+// ClaimDue moves the last run of every due saved search to now and returns
+// the claimed searches with their previous last run. Rows locked by another
+// instance are skipped, so each search is only picked up once.
+func (r *SavedSearchRepository) ClaimDue(now time.Time) ([]carwise.SavedSearch, error) {
+	query := `
+		WITH due AS (
+			SELECT s.id, s.last_run_at
+			FROM saved_searches s
+			JOIN users u ON u.id = s.user_id
+			WHERE u.status = $1 AND u.email_verified = TRUE AND u.deletion_requested_at IS NULL AND (
+				s.frequency = $2 OR 
+				(s.frequency = $3 AND s.last_run_at <= $4) OR 
+				(s.frequency = $5 AND s.last_run_at <= $6)
+			)
+			FOR UPDATE OF s SKIP LOCKED
+		)
+		UPDATE saved_searches s 
+		SET last_run_at = $7 
+		FROM due 
+		WHERE s.id = due.id 
+		RETURNING 
+			s.id, 
+			s.user_id, 
+			s.name, 
+			s.brand_id, 
+			s.series_id, 
+			s.model_id, 
+			s.frequency, 
+			s.unsubscribe_token, 
+			due.last_run_at, 
+			s.created_at
+	`
+	return r.query(query,
+		carwise.AccountStatusActive,
+		carwise.AlertFrequencyInstant,
+		carwise.AlertFrequencyDaily, now.Add(-24*time.Hour),
+		carwise.AlertFrequencyWeekly, now.Add(-7*24*time.Hour),
+		now,
+	)
+}
+
+func (r *SavedSearchRepository) MarkRun(id string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE saved_searches SET last_run_at = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return nil
+}
+
+func (r *SavedSearchRepository) query(query string, args ...interface{}) ([]carwise.SavedSearch, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []carwise.SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved search: %w", err)
+		}
+		searches = append(searches, search)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return searches, nil
+}
+
+func scanSavedSearch(row rowScanner) (carwise.SavedSearch, error) {
+	var search carwise.SavedSearch
+	err := row.Scan(
+		&search.ID,
+		&search.UserID,
+		&search.Name,
+		&search.BrandID,
+		&search.SeriesID,
+		&search.ModelID,
+		&search.Frequency,
+		&search.UnsubscribeToken,
+		&search.LastRunAt,
+		&search.CreatedAt,
+	)
+	return search, err
+}
@@ -1,8 +1,11 @@
 package infra
 
 import (
+	"carwise"
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -39,3 +42,78 @@ func (r *TokenRepository) AddTokenBlackList(token string) error {
 
 	return nil
 }
+
+func (r *TokenRepository) RevokeUserTokens(userID string, at time.Time) error {
+	key := fmt.Sprintf("token-revoked:%s", userID)
+	err := r.client.Set(context.Background(), key, at.Unix(), 365*24*time.Hour).Err()
+	if err != nil {
+		return fmt.Errorf("failed to revoke user tokens in Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *TokenRepository) GetUserTokensRevokedAt(userID string) (time.Time, error) {
+	key := fmt.Sprintf("token-revoked:%s", userID)
+	val, err := r.client.Get(context.Background(), key).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to check token revocation in Redis: %v", err)
+	}
+
+	revokedAt, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid token revocation timestamp: %v", err)
+	}
+
+	return time.Unix(revokedAt, 0), nil
+}
+
+func (r *TokenRepository) SaveEventTicket(ticketHash string, session *carwise.EventSession, ttl time.Duration) error {
+	key := fmt.Sprintf("event-ticket:%s", ticketHash)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user_id", session.UserID, "token", session.Token, "issued_at", session.IssuedAt)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save event ticket to Redis: %v", err)
+	}
+
+	return nil
+}
+
+// ConsumeEventTicket reads and deletes the ticket in one transaction, so a
+// ticket opens at most one stream.
+func (r *TokenRepository) ConsumeEventTicket(ticketHash string) (*carwise.EventSession, error) {
+	key := fmt.Sprintf("event-ticket:%s", ticketHash)
+	ctx := context.Background()
+
+	var values *redis.MapStringStringCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume event ticket in Redis: %v", err)
+	}
+	if len(values.Val()) == 0 {
+		return nil, nil
+	}
+
+	issuedAt, err := strconv.ParseInt(values.Val()["issued_at"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event ticket timestamp: %v", err)
+	}
+
+	return &carwise.EventSession{
+		UserID:   values.Val()["user_id"],
+		Token:    values.Val()["token"],
+		IssuedAt: issuedAt,
+	}, nil
+}
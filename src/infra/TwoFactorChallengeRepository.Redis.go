@@ -0,0 +1,76 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type TwoFactorChallengeRepository struct {
+	client *redis.Client
+}
+
+func NewTwoFactorChallengeRepository() *TwoFactorChallengeRepository {
+	return &TwoFactorChallengeRepository{client: ConnectRedis()}
+}
+
+func (r *TwoFactorChallengeRepository) SaveChallenge(token, userID string, ttl time.Duration) error {
+	key := fmt.Sprintf("2fa-challenge:%s", token)
+	ctx := context.Background()
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user_id", userID, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save two-factor challenge to Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorChallengeRepository) GetChallenge(token string) (string, error) {
+	key := fmt.Sprintf("2fa-challenge:%s", token)
+	userID, err := r.client.HGet(context.Background(), key, "user_id").Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve two-factor challenge from Redis: %v", err)
+	}
+
+	return userID, nil
+}
+
+func (r *TwoFactorChallengeRepository) IncrementChallengeAttempts(token string) (int64, error) {
+	key := fmt.Sprintf("2fa-challenge:%s", token)
+	attempts, err := r.client.HIncrBy(context.Background(), key, "attempts", 1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment two-factor attempts in Redis: %v", err)
+	}
+
+	return attempts, nil
+}
+
+func (r *TwoFactorChallengeRepository) DeleteChallenge(token string) error {
+	key := fmt.Sprintf("2fa-challenge:%s", token)
+	err := r.client.Del(context.Background(), key).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete two-factor challenge from Redis: %v", err)
+	}
+
+	return nil
+}
+
+func (r *TwoFactorChallengeRepository) MarkCodeUsed(userID string, step int64, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("2fa-used:%s:%d", userID, step)
+	fresh, err := r.client.SetNX(context.Background(), key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to record used two-factor code in Redis: %v", err)
+	}
+
+	return fresh, nil
+}
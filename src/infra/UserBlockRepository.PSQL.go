@@ -0,0 +1,80 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+)
+
+type UserBlockRepository struct {
+	db *sql.DB
+}
+
+func NewUserBlockRepository() *UserBlockRepository {
+	database := ConnectDb()
+	return &UserBlockRepository{db: database}
+}
+
+func (r *UserBlockRepository) Block(block *carwise.UserBlock) error {
+	query := `
+		INSERT INTO user_blocks (
+			blocker_id, 
+			blocked_id, 
+			created_at
+		) VALUES (
+			$1, $2, $3
+		) 
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING`
+	_, err := r.db.Exec(query, block.BlockerID, block.BlockedID, block.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserBlockRepository) Unblock(blockerID, blockedID string) error {
+	_, err := r.db.Exec(`DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`, blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("failed to unblock user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserBlockRepository) IsBlocked(userID, otherID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 
+			FROM user_blocks 
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)`
+
+	var blocked bool
+	err := r.db.QueryRow(query, userID, otherID).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	return blocked, nil
+}
+
+func (r *UserBlockRepository) GetBlockedIDs(blockerID string) (map[string]bool, error) {
+	rows, err := r.db.Query(`SELECT blocked_id FROM user_blocks WHERE blocker_id = $1`, blockerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blocks: %w", err)
+	}
+	defer rows.Close()
+
+	blocked := make(map[string]bool)
+	for rows.Next() {
+		var blockedID string
+		if err := rows.Scan(&blockedID); err != nil {
+			return nil, fmt.Errorf("failed to scan block: %w", err)
+		}
+		blocked[blockedID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return blocked, nil
+}
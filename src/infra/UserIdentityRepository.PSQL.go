@@ -0,0 +1,114 @@
+package infra
+
+import (
+	"carwise"
+	"database/sql"
+	"fmt"
+)
+
+type UserIdentityRepository struct {
+	db *sql.DB
+}
+
+func NewUserIdentityRepository() *UserIdentityRepository {
+	database := ConnectDb()
+	return &UserIdentityRepository{db: database}
+}
+
+func (r *UserIdentityRepository) Create(identity *carwise.UserIdentity) error {
+	query := `
+		INSERT INTO user_identities (
+			id, 
+			user_id, 
+			provider, 
+			subject, 
+			email, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6
+		)`
+	_, err := r.db.Exec(query,
+		identity.ID,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
+
+func (r *UserIdentityRepository) GetByProviderSubject(provider, subject string) (*carwise.UserIdentity, error) {
+	query := `
+		SELECT 
+			id, 
+			user_id, 
+			provider, 
+			subject, 
+			email, 
+			created_at 
+		FROM user_identities 
+		WHERE provider = $1 AND subject = $2`
+
+	identity := &carwise.UserIdentity{}
+	err := r.db.QueryRow(query, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query user identity: %w", err)
+	}
+	return identity, nil
+}
+
+func (r *UserIdentityRepository) GetByUser(userID string) ([]carwise.UserIdentity, error) {
+	query := `
+		SELECT 
+			id, 
+			user_id, 
+			provider, 
+			subject, 
+			email, 
+			created_at 
+		FROM user_identities 
+		WHERE user_id = $1 
+		ORDER BY created_at`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user identities: %w", err)
+	}
+	defer rows.Close()
+
+	var identities []carwise.UserIdentity
+	for rows.Next() {
+		var identity carwise.UserIdentity
+		if err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan user identity: %w", err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return identities, nil
+}
@@ -5,54 +5,50 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 )
 
-type UserRepository struct {
-	db *sql.DB
-}
-
-func NewUserRepository() *UserRepository {
-	database := ConnectDb()
-	return &UserRepository{db: database}
-}
-
-func (r *UserRepository) GetByID(id string) (*carwise.User, error) {
-	user := &carwise.User{}
-	query := `
-		SELECT 
+const userColumns = `
 			id, 
 			first_name, 
 			last_name, 
 			image_url, 
 			country_code, 
-			phone_number, 
+			COALESCE(phone_number, ''), 
 			email, 
 			password_hash, 
 			role, 
 			status, 
+			email_verified, 
+			phone_verified, 
+			two_factor_enabled, 
+			two_factor_secret, 
+			show_phone_number, 
+			locale, 
+			deletion_requested_at, 
 			created_at, 
 			updated_at, 
-			last_login 
+			last_login `
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository() *UserRepository {
+	database := ConnectDb()
+	return &UserRepository{db: database}
+}
+
+func (r *UserRepository) GetByID(id string) (*carwise.User, error) {
+	query := `
+		SELECT ` + userColumns + `
 		FROM users 
 		WHERE id = $1`
-	err := r.db.QueryRow(query, id).Scan(
-		&user.ID,
-		&user.FirstName,
-		&user.LastName,
-		&user.ImageUrl,
-		&user.CountryCode,
-		&user.PhoneNumber,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Role,
-		&user.Status,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&user.LastLogin,
-	)
+	user, err := scanUser(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, carwise.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to query user by ID: %w", err)
 	}
@@ -60,42 +56,14 @@ func (r *UserRepository) GetByID(id string) (*carwise.User, error) {
 }
 
 func (r *UserRepository) GetByEmail(email string) (*carwise.User, error) {
-	user := &carwise.User{}
 	query := `
-		SELECT 
-			id, 
-			first_name, 
-			last_name, 
-			image_url, 
-			country_code, 
-			phone_number, 
-			email, 
-			password_hash, 
-			role, 
-			status, 
-			created_at, 
-			updated_at, 
-			last_login 
+		SELECT ` + userColumns + `
 		FROM users 
 		WHERE email = $1`
-	err := r.db.QueryRow(query, email).Scan(
-		&user.ID,
-		&user.FirstName,
-		&user.LastName,
-		&user.ImageUrl,
-		&user.CountryCode,
-		&user.PhoneNumber,
-		&user.Email,
-		&user.PasswordHash,
-		&user.Role,
-		&user.Status,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&user.LastLogin,
-	)
+	user, err := scanUser(r.db.QueryRow(query, email))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, errors.New("user not found")
+			return nil, carwise.ErrUserNotFound
 		}
 		return nil, fmt.Errorf("failed to query user by Email: %w", err)
 	}
@@ -115,11 +83,17 @@ func (r *UserRepository) Create(user *carwise.User) error {
 			password_hash, 
 			role, 
 			status, 
+			email_verified, 
+			phone_verified, 
+			two_factor_enabled, 
+			two_factor_secret, 
+			show_phone_number, 
+			locale, 
 			created_at, 
 			updated_at, 
 			last_login
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+			$1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 		)`
 	_, err := r.db.Exec(query,
 		user.ID,
@@ -132,6 +106,12 @@ func (r *UserRepository) Create(user *carwise.User) error {
 		user.PasswordHash,
 		user.Role,
 		user.Status,
+		user.EmailVerified,
+		user.PhoneVerified,
+		user.TwoFactorEnabled,
+		user.TwoFactorSecret,
+		user.ShowPhoneNumber,
+		user.Locale,
 		user.CreatedAt,
 		user.UpdatedAt,
 		user.LastLogin,
@@ -167,6 +147,32 @@ func (r *UserRepository) UpdatePassword(email, hashedPassword string) error {
 	return nil
 }
 
+func (r *UserRepository) UpdateEmail(id, email string) error {
+	query := `
+		UPDATE users 
+		SET 
+			email = $1, 
+			email_verified = TRUE, 
+			updated_at = NOW() 
+		WHERE id = $2`
+
+	result, err := r.db.Exec(query, email, id)
+	if err != nil {
+		return fmt.Errorf("failed to update email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return carwise.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepository) Update(user *carwise.User) error {
 	query := `
         UPDATE users 
@@ -175,13 +181,334 @@ func (r *UserRepository) Update(user *carwise.User) error {
             last_name = $2,
             image_url = $3,
 			country_code = $4,
-			phone_number = $5,
+			phone_number = NULLIF($5, ''),
+			email_verified = $6,
+			phone_verified = $7,
+			two_factor_enabled = $8,
+			two_factor_secret = $9,
+			show_phone_number = $10,
+			locale = $11,
             updated_at = NOW()
-        WHERE id = $6`
+        WHERE id = $12`
 
-	_, err := r.db.Exec(query, user.FirstName, user.LastName, user.ImageUrl, user.CountryCode, user.PhoneNumber, user.ID)
+	_, err := r.db.Exec(query, user.FirstName, user.LastName, user.ImageUrl, user.CountryCode, user.PhoneNumber, user.EmailVerified, user.PhoneVerified, user.TwoFactorEnabled, user.TwoFactorSecret, user.ShowPhoneNumber, user.Locale, user.ID)
 	if err != nil {
 		return fmt.Errorf("failed to update user: %w", err)
 	}
 	return nil
 }
+
+func (r *UserRepository) UpdateStatus(id, status string) error {
+	query := `
+		UPDATE users 
+		SET 
+			status = $1, 
+			updated_at = NOW() 
+		WHERE id = $2`
+
+	_, err := r.db.Exec(query, status, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user status: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) UpdateRole(id, role string) error {
+	query := `
+		UPDATE users 
+		SET 
+			role = $1, 
+			updated_at = NOW() 
+		WHERE id = $2`
+
+	_, err := r.db.Exec(query, role, id)
+	if err != nil {
+		return fmt.Errorf("failed to update user role: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) Search(query, role, status string, page, limit int) ([]carwise.User, error) {
+	offset := (page - 1) * limit
+
+	sqlQuery := `
+		SELECT ` + userColumns + `
+		FROM users
+	`
+	conditions := []string{}
+	args := []interface{}{}
+
+	if query != "" {
+		placeholder := "$" + fmt.Sprint(len(args)+1)
+		conditions = append(conditions, "(first_name ILIKE "+placeholder+" OR last_name ILIKE "+placeholder+" OR email ILIKE "+placeholder+" OR phone_number ILIKE "+placeholder+")")
+		args = append(args, "%"+query+"%")
+	}
+	if role != "" {
+		conditions = append(conditions, "role = $"+fmt.Sprint(len(args)+1))
+		args = append(args, role)
+	}
+	if status != "" {
+		conditions = append(conditions, "status = $"+fmt.Sprint(len(args)+1))
+		args = append(args, status)
+	}
+
+	if len(conditions) > 0 {
+		sqlQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	sqlQuery += " ORDER BY created_at DESC LIMIT $" + fmt.Sprint(len(args)+1) + " OFFSET $" + fmt.Sprint(len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []carwise.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) AddStatusChange(change *carwise.UserStatusChange) error {
+	query := `
+		INSERT INTO user_status_changes (
+			id, 
+			user_id, 
+			changed_by, 
+			old_status, 
+			new_status, 
+			reason, 
+			created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)`
+	_, err := r.db.Exec(query,
+		change.ID,
+		change.UserID,
+		change.ChangedBy,
+		change.OldStatus,
+		change.NewStatus,
+		change.Reason,
+		change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) GetStatusChanges(userID string) ([]carwise.UserStatusChange, error) {
+	query := `
+		SELECT 
+			id, 
+			user_id, 
+			changed_by, 
+			old_status, 
+			new_status, 
+			reason, 
+			created_at
+		FROM user_status_changes
+		WHERE user_id = $1
+		ORDER BY created_at DESC`
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch status changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []carwise.UserStatusChange
+	for rows.Next() {
+		var change carwise.UserStatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.ChangedBy,
+			&change.OldStatus,
+			&change.NewStatus,
+			&change.Reason,
+			&change.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return changes, nil
+}
+
+func (r *UserRepository) UpdateLastLogin(id string, at time.Time) error {
+	query := `
+		UPDATE users 
+		SET 
+			last_login = $1 
+		WHERE id = $2`
+
+	_, err := r.db.Exec(query, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to update last login: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) ScheduleDeletion(id string, at time.Time) error {
+	query := `
+		UPDATE users 
+		SET 
+			deletion_requested_at = $1, 
+			updated_at = NOW() 
+		WHERE id = $2`
+
+	_, err := r.db.Exec(query, at, id)
+	if err != nil {
+		return fmt.Errorf("failed to schedule deletion: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) CancelDeletion(id string) error {
+	query := `
+		UPDATE users 
+		SET 
+			deletion_requested_at = NULL, 
+			updated_at = NOW() 
+		WHERE id = $1`
+
+	_, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to cancel deletion: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) GetDeletionsDue(before time.Time) ([]carwise.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM users 
+		WHERE deletion_requested_at IS NOT NULL AND deletion_requested_at <= $1`
+
+	rows, err := r.db.Query(query, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var users []carwise.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rows: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) Anonymize(user *carwise.User) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, query := range []string{
+		`DELETE FROM cars WHERE owner_id = $1 AND organization_id IS NULL`,
+		`DELETE FROM organization_members WHERE user_id = $1`,
+		`DELETE FROM favorites WHERE user_id = $1`,
+		`DELETE FROM saved_searches WHERE user_id = $1`,
+		`DELETE FROM conversations WHERE seller_id = $1 OR buyer_id = $1`,
+		`DELETE FROM offers WHERE seller_id = $1 OR buyer_id = $1`,
+		`DELETE FROM notifications WHERE user_id = $1`,
+		`DELETE FROM notification_preferences WHERE user_id = $1`,
+		`DELETE FROM user_blocks WHERE blocker_id = $1 OR blocked_id = $1`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+		`DELETE FROM user_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM login_events WHERE user_id = $1`,
+		`DELETE FROM contact_reveals WHERE viewer_id = $1`,
+	} {
+		_, err = tx.Exec(query, user.ID)
+		if err != nil {
+			return fmt.Errorf("failed to delete user data: %w", err)
+		}
+	}
+
+	query := `
+		UPDATE users 
+		SET 
+			first_name = $1, 
+			last_name = $2, 
+			image_url = $3, 
+			country_code = $4, 
+			phone_number = NULLIF($5, ''), 
+			email = $6, 
+			password_hash = $7, 
+			status = $8, 
+			email_verified = $9, 
+			phone_verified = $10, 
+			two_factor_enabled = $11, 
+			two_factor_secret = $12, 
+			show_phone_number = FALSE, 
+			deletion_requested_at = NULL, 
+			updated_at = NOW() 
+		WHERE id = $13`
+
+	_, err = tx.Exec(query, user.FirstName, user.LastName, user.ImageUrl, user.CountryCode, user.PhoneNumber, user.Email, user.PasswordHash, user.Status, user.EmailVerified, user.PhoneVerified, user.TwoFactorEnabled, user.TwoFactorSecret, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize user: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit user deletion: %w", err)
+	}
+
+	return nil
+}
+
+func scanUser(row rowScanner) (*carwise.User, error) {
+	user := &carwise.User{}
+	var deletionRequestedAt sql.NullTime
+	err := row.Scan(
+		&user.ID,
+		&user.FirstName,
+		&user.LastName,
+		&user.ImageUrl,
+		&user.CountryCode,
+		&user.PhoneNumber,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+		&user.Status,
+		&user.EmailVerified,
+		&user.PhoneVerified,
+		&user.TwoFactorEnabled,
+		&user.TwoFactorSecret,
+		&user.ShowPhoneNumber,
+		&user.Locale,
+		&deletionRequestedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+		&user.LastLogin,
+	)
+	user.DeletionRequestedAt = deletionRequestedAt.Time
+	return user, err
+}